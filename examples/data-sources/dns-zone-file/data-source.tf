@@ -0,0 +1,15 @@
+# Migrate a DNS zone exported from BIND
+data "ngenix_dns_zone_file" "example" {
+  content = file("${path.module}/example.ru.zone")
+}
+
+resource "ngenix_dnszone" "example" {
+  name        = "example.ru"
+  dns_records = data.ngenix_dns_zone_file.example.dns_records
+}
+
+# Archive the records of the DNS zone in the zone file format
+resource "local_file" "example_zone" {
+  filename = "${path.module}/archive/example.ru.zone"
+  content  = ngenix_dnszone.example.zone_file
+}
@@ -0,0 +1,8 @@
+# DNS zone looked up by name
+data "ngenix_dns_zone" "example" {
+  name = "example.com"
+}
+
+output "www_addresses" {
+  value = [for record in data.ngenix_dns_zone.example.records_by_name["www"] : record.data if record.type == "A"]
+}
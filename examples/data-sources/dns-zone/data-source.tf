@@ -1,3 +1,10 @@
 # List of all DNS zones
 data "ngenix_dns_zones" "all" {}
 
+
+# DNS zones with CDN records referencing the config
+data "ngenix_dns_zones" "cdn" {
+  name_regex    = "\\.example\\.com$"
+  record_type   = "A"
+  config_ref_id = 88903
+}
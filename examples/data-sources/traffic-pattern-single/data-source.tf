@@ -0,0 +1,13 @@
+# Read-only Traffic pattern managed by Ngenix looked up by name
+data "ngenix_traffic_pattern" "blacklist" {
+  name = "blacklist"
+}
+
+# Traffic pattern looked up by ID
+data "ngenix_traffic_pattern" "example" {
+  id = "1234"
+}
+
+output "blacklist_id" {
+  value = data.ngenix_traffic_pattern.blacklist.id
+}
@@ -1,3 +1,10 @@
 # List of all Traffic patterns
 data "ngenix_traffic_patterns" "example" {}
 
+
+# Read-only address lists with the names starting with "office-"
+data "ngenix_traffic_patterns" "office" {
+  name_regex   = "^office-"
+  type         = "blacklist"
+  content_type = "addr"
+}
@@ -4,3 +4,28 @@ provider "ngenix" {
   username = "NGENIX_USERNAME_EMAIL"
   password = "NGENIX_USERNAME_TOKEN"
 }
+
+# Failed requests retried with exponential backoff
+provider "ngenix" {
+  alias           = "retrying"
+  host            = "https://api.ngenix.net/api/v3/"
+  username        = "NGENIX_USERNAME_EMAIL"
+  password        = "NGENIX_USERNAME_TOKEN"
+  max_retries     = 5
+  retry_wait_min  = "500ms"
+  retry_wait_max  = "1m"
+  request_timeout = "30s"
+}
+
+# Credentials of the staging profile of ~/.ngenix/credentials
+provider "ngenix" {
+  alias   = "staging"
+  profile = "staging"
+}
+
+# Credentials of a profile of another credentials file
+provider "ngenix" {
+  alias            = "production"
+  profile          = "production"
+  credentials_file = "~/.config/ngenix/credentials.yaml"
+}
@@ -0,0 +1,8 @@
+# DNS record can be imported by specifying the DNS zone ID, record name, record type and record value
+terraform import ngenix_dns_record.example 6184123/team/CNAME/team.ngenix.net.
+
+# The value of an A record with a reference is config_ref:<id> or targetgroup_ref:<id>
+terraform import ngenix_dns_record.example_config_ref 6184123/cdn/A/config_ref:52835
+
+# Records of a multi-value RRset are imported one by one
+terraform import 'ngenix_dns_record.mx["10"]' "6184123/@/MX/10 mx1.example.com."
@@ -0,0 +1,39 @@
+# Manage a single DNS record in a DNS zone owned by another configuration
+resource "ngenix_dns_record" "example" {
+  zone_id = "6184123"
+  name    = "team"
+  type    = "CNAME"
+  data    = "team.ngenix.net."
+}
+
+resource "ngenix_dns_record" "example_config_ref" {
+  zone_id = "6184123"
+  name    = "cdn"
+  type    = "A"
+  config_ref = {
+    id = 52835
+  }
+}
+
+resource "ngenix_dns_record" "example_caa" {
+  zone_id = "6184123"
+  name    = "@"
+  type    = "CAA"
+  data    = "letsencrypt.org"
+  flags   = 0
+  tag     = "issue"
+}
+
+# Every record of a multi-value RRset is a separate resource
+resource "ngenix_dns_record" "mx" {
+  for_each = {
+    "10" = "mx1.example.com."
+    "20" = "mx2.example.com."
+  }
+
+  zone_id  = "6184123"
+  name     = "@"
+  type     = "MX"
+  data     = each.value
+  priority = tonumber(each.key)
+}
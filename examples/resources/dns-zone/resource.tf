@@ -21,7 +21,16 @@ resource "ngenix_dnszone" "example" {
       {
         name = "_internal._protocol.name",
         type = "SRV",
-        data = "10 5 2000 test.srv.test."
+        data = "test.srv.test.",
+        priority = 10,
+        weight = 5,
+        port = 2000
+      },
+      {
+        name = "@",
+        type = "MX",
+        data = "mail.example.ru.",
+        priority = 10
       },
       {
         name = "@",
@@ -30,4 +39,9 @@ resource "ngenix_dnszone" "example" {
       }
     ]
   }
-}
\ No newline at end of file
+
+  timeouts {
+    create = "30m"
+    delete = "30m"
+  }
+}
@@ -0,0 +1,2 @@
+# Traffic pattern entry can be imported by specifying the Traffic pattern ID and the entry value
+terraform import ngenix_traffic_pattern_entry.example 4321/203.0.113.0/24
@@ -0,0 +1,7 @@
+# Manage a single entry of a shared Traffic pattern example
+resource "ngenix_traffic_pattern_entry" "incident" {
+  pattern_id = "4321"
+  value      = "203.0.113.0/24"
+  ttl        = 86400
+  comment    = "Incident 42"
+}
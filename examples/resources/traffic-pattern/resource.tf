@@ -5,12 +5,16 @@ resource "ngenix_traffic_pattern" "testaddrex" {
   content_type = "addr"
   patterns = [
     {
-      addr    = "98.164.15.2"
+      addr    = "98.164.15.2/32"
       expires = 1924166191
     },
     {
-      addr    = "23.56.67.89"
+      addr    = "23.56.67.89/32"
       expires = 1924165191
+    },
+    {
+      addr = "2001:db8:1::/48"
+      ttl  = 3600
     }
   ]
 }
@@ -30,4 +34,62 @@ resource "ngenix_traffic_pattern" "testhttpex" {
       http_method = "DELETE"
     }
   ]
-}
\ No newline at end of file
+}
+# Addresses are collapsed to 10.1.2.0/23 before sending them to Ngenix
+resource "ngenix_traffic_pattern" "testnormalizeex" {
+  name                = "test-normalize-ex"
+  type                = "commonlist"
+  content_type        = "addr"
+  normalize_addresses = true
+  patterns = [
+    {
+      addr = "10.1.2.3/24"
+    },
+    {
+      addr = "10.1.3.0/24"
+    }
+  ]
+}
+
+# Patterns loaded from a file with one address per line and optional "# comment",
+# only the hash of the file is stored in the state
+resource "ngenix_traffic_pattern" "testsourceex" {
+  name         = "test-source-ex"
+  type         = "commonlist"
+  content_type = "addr"
+  source_file  = "${path.module}/allowed-networks.txt"
+}
+
+resource "ngenix_traffic_pattern" "testsourcecsvex" {
+  name           = "test-source-csv-ex"
+  type           = "commonlist"
+  content_type   = "countryCode"
+  source_format  = "csv"
+  source_content = <<-EOT
+    country_code,comment
+    DE,Germany
+    FR,France
+  EOT
+}
+
+# Temporary blocks expire on the Ngenix side, the expired patterns are listed
+# in expired_patterns and are not added again unless recreate_expired is set
+resource "ngenix_traffic_pattern" "testexpiryex" {
+  name         = "test-expiry-ex"
+  type         = "commonlist"
+  content_type = "addr"
+  patterns = [
+    {
+      addr       = "198.51.100.0/24"
+      expires_at = "72h"
+    },
+    {
+      addr       = "203.0.113.0/24"
+      expires_at = "2030-12-31T23:59:59Z"
+    }
+  ]
+
+  timeouts {
+    update = "30m"
+  }
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// maxAPIErrorBodySize limits the error response body read by apiErrorTransport.
+const maxAPIErrorBodySize = 1 << 20
+
+// APIError is the error response of the Ngenix API: the status code and the
+// message, the code and violations are only known for the error format
+// assumed by newAPIError. It wraps the error returned by the core library,
+// so e.g. isNotFound(err) still reports a missing object.
+type APIError struct {
+	StatusCode int
+	// Code is the machine-readable error code, e.g. validation_error.
+	Code    string
+	Message string
+	// Violations lists the rejected fields of the request body.
+	Violations []APIFieldViolation
+
+	err error
+}
+
+// APIFieldViolation is a rejected field of the request body in the assumed
+// error format, the field is the path of the Ngenix API object, e.g. records[3].data.
+type APIFieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Ngenix API error (HTTP %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", %s", e.Code)
+	}
+	b.WriteString(")")
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	for i, violation := range e.Violations {
+		if i == 0 {
+			b.WriteString(":")
+		} else {
+			b.WriteString(";")
+		}
+		fmt.Fprintf(&b, " %s %s", violation.Field, violation.Message)
+	}
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// newAPIError parses the error response body. The body is used as the message
+// unless it has the JSON error format {code, message, errors: [{field, message}]},
+// which is assumed: the Ngenix API documentation does not describe its error
+// responses and the format is not verified against the platform.
+func newAPIError(statusCode int, body []byte) *APIError {
+	var response struct {
+		Code    string              `json:"code"`
+		Message string              `json:"message"`
+		Errors  []APIFieldViolation `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return &APIError{StatusCode: statusCode, Message: strings.TrimSpace(truncateBody(body, int64(len(body))))}
+	}
+	return &APIError{
+		StatusCode: statusCode,
+		Code:       response.Code,
+		Message:    response.Message,
+		Violations: response.Errors,
+	}
+}
+
+// isNotFound reports whether the Ngenix API answered the call with HTTP 404,
+// the core library has no typed error for a missing object.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// apiErrorTransport records the last error response of a core library call,
+// the core library reports it as a flat string.
+type apiErrorTransport struct {
+	next http.RoundTripper
+	last *APIError
+}
+
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+	// The body is read and replaced by the read copy for the core library.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIErrorBodySize))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	t.last = newAPIError(resp.StatusCode, body)
+	return resp, nil
+}
+
+// apiField is a parsed field of APIFieldViolation, e.g. records[3].data is
+// the data attribute of the element with index 3 of records.
+type apiField struct {
+	name      string
+	index     int
+	attribute string
+}
+
+var apiFieldRegexp = regexp.MustCompile(`^(\w+)(?:\[(\d+)\])?(?:\.(\w+))?$`)
+
+// parseAPIField parses the field of the violation, the index is -1 if the
+// field is not a list element.
+func parseAPIField(field string) (apiField, bool) {
+	match := apiFieldRegexp.FindStringSubmatch(field)
+	if match == nil {
+		return apiField{}, false
+	}
+	parsed := apiField{name: match[1], index: -1, attribute: match[3]}
+	if match[2] != "" {
+		index, err := strconv.Atoi(match[2])
+		if err != nil {
+			return apiField{}, false
+		}
+		parsed.index = index
+	}
+	return parsed, true
+}
+
+// apiAttributeNames lists the Ngenix API fields named differently from the
+// snake case attributes of the provider.
+var apiAttributeNames = map[string]string{
+	"records":        "dns_records",
+	"targetGroupRef": "targetgroup_ref",
+	"md5HashString":  "md5hash_string",
+}
+
+// attributeName returns the attribute name of the Ngenix API field, e.g. content_type of contentType.
+func attributeName(field string) string {
+	if name, ok := apiAttributeNames[field]; ok {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range field {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// addAPIErrorDiagnostics adds the error of a core library call to the
+// diagnostics. The field violations of an APIError are added as attribute
+// errors on the paths returned by fieldPath, the violations of the fields
+// without a path are added as errors. Other errors, e.g. of an error response
+// not in the assumed format, are added as a single error with the detail
+// followed by the error.
+func addAPIErrorDiagnostics(diags *diag.Diagnostics, summary, detail string, err error, fieldPath func(field apiField) (path.Path, bool)) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Violations) == 0 {
+		diags.AddError(summary, fmt.Sprintf("%s: %s", detail, err.Error()))
+		return
+	}
+	for _, violation := range apiErr.Violations {
+		if field, ok := parseAPIField(violation.Field); ok && fieldPath != nil {
+			if attributePath, ok := fieldPath(field); ok {
+				diags.AddAttributeError(attributePath, summary, fmt.Sprintf("%s: the value was rejected by Ngenix API, %s", detail, violation.Message))
+				continue
+			}
+		}
+		diags.AddError(summary, fmt.Sprintf("%s: %s was rejected by Ngenix API, %s", detail, violation.Field, violation.Message))
+	}
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNgenixClientAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			writeFakeError(w, http.StatusNotFound, "not_found", "dns zone not found")
+			return
+		}
+		writeFakeValidationError(w, fakeFieldError{Field: "records[1].data", Message: "must not be empty"})
+	}))
+	t.Cleanup(server.Close)
+	client := newNgenixClient(&restapi.Client{}, nil, nil)
+
+	// coreLibraryCall reports the error responses as flat strings, as the core library does.
+	coreLibraryCall := func(url string) func(client *restapi.Client) error {
+		return func(*restapi.Client) error {
+			resp, err := http.Get(url)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("status: %d, body: %s", resp.StatusCode, body)
+		}
+	}
+
+	err := client.call(context.Background(), "CreateDnsZone", coreLibraryCall(server.URL+"/dns-zone"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got: %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "validation_error" || apiErr.Message != "request validation failed" {
+		t.Errorf("unexpected APIError: %#v", apiErr)
+	}
+	if len(apiErr.Violations) != 1 || apiErr.Violations[0] != (APIFieldViolation{Field: "records[1].data", Message: "must not be empty"}) {
+		t.Errorf("unexpected violations: %v", apiErr.Violations)
+	}
+	// The core library still reads the whole body.
+	if !strings.Contains(errors.Unwrap(err).Error(), `"field":"records[1].data"`) {
+		t.Errorf("expected the core library error with the body, got: %v", errors.Unwrap(err))
+	}
+	if isNotFound(err) {
+		t.Error("expected the validation error not to be reported as not found")
+	}
+	if expected := "Ngenix API error (HTTP 400, validation_error): request validation failed: records[1].data must not be empty"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	err = client.call(context.Background(), "GetDnsZoneById", coreLibraryCall(server.URL+"/missing"))
+	if !errors.As(err, &apiErr) || apiErr.Code != "not_found" || !isNotFound(err) {
+		t.Errorf("expected not found APIError, got: %v", err)
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	apiErr := newAPIError(http.StatusBadGateway, []byte("<html>Bad Gateway</html>\n"))
+	if apiErr.Message != "<html>Bad Gateway</html>" || apiErr.Error() != "Ngenix API error (HTTP 502): <html>Bad Gateway</html>" {
+		t.Errorf("expected the body as the message, got: %q", apiErr.Error())
+	}
+}
+
+func TestParseAPIField(t *testing.T) {
+	tests := map[string]struct {
+		field string
+		want  apiField
+		ok    bool
+	}{
+		"attribute":         {field: "name", want: apiField{name: "name", index: -1}, ok: true},
+		"element":           {field: "records[3]", want: apiField{name: "records", index: 3}, ok: true},
+		"element attribute": {field: "records[3].data", want: apiField{name: "records", index: 3, attribute: "data"}, ok: true},
+		"nested attribute":  {field: "customerRef.id", want: apiField{name: "customerRef", index: -1, attribute: "id"}, ok: true},
+		"empty":             {field: "", ok: false},
+		"deeply nested":     {field: "records[3].configRef.id", ok: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := parseAPIField(test.field)
+			if ok != test.ok || (ok && got != test.want) {
+				t.Errorf("expected %+v, %t, got %+v, %t", test.want, test.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestAttributeName(t *testing.T) {
+	for field, name := range map[string]string{
+		"name":           "name",
+		"contentType":    "content_type",
+		"configRef":      "config_ref",
+		"targetGroupRef": "targetgroup_ref",
+		"md5HashString":  "md5hash_string",
+		"records":        "dns_records",
+	} {
+		if got := attributeName(field); got != name {
+			t.Errorf("%s: expected %s, got %s", field, name, got)
+		}
+	}
+}
+
+// attributeErrorPaths returns the paths of the attribute errors and the number of the other errors.
+func attributeErrorPaths(diags diag.Diagnostics) ([]path.Path, int) {
+	paths := []path.Path{}
+	other := 0
+	for _, d := range diags {
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			paths = append(paths, withPath.Path())
+			continue
+		}
+		other++
+	}
+	return paths, other
+}
+
+func TestDnsZoneFieldPath(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp fwresource.SchemaResponse
+	(&dnsZoneResource{}).Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	model := dnsZoneResourceModel{
+		Name: types.StringValue("example.ru"),
+		Records: []dnsRecordsItemModel{
+			{Name: types.StringValue("www"), Type: types.StringValue("A"), Data: types.StringValue("192.0.2.10")},
+			{Name: types.StringValue("mail"), Type: types.StringValue("TXT"), Data: types.StringValue("v=spf1 -all")},
+		},
+		Timeouts: nullTimeouts(),
+	}
+	plan := tfsdk.Plan{Schema: schemaResp.Schema}
+	if diags := plan.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("unexpected error setting the plan: %v", diags)
+	}
+	var recordsSet types.Set
+	if diags := plan.GetAttribute(ctx, path.Root("dns_records"), &recordsSet); diags.HasError() {
+		t.Fatalf("unexpected error reading the records: %v", diags)
+	}
+
+	err := &APIError{StatusCode: http.StatusBadRequest, Code: "validation_error", Violations: []APIFieldViolation{
+		{Field: "records[1].data", Message: "must be a quoted string"},
+		{Field: "name", Message: "is already taken"},
+		// The unmanaged records are sent after the planned ones.
+		{Field: "records[2].data", Message: "must not be empty"},
+		{Field: "customerRef.id", Message: "is unknown"},
+	}}
+	var diags diag.Diagnostics
+	addAPIErrorDiagnostics(&diags, "Error creating DNS zone", "Could not create DNS Zone", err, dnsZoneFieldPath(ctx, recordsSet, model.Records))
+
+	var mailRecord path.Path
+	for _, element := range recordsSet.Elements() {
+		if object, ok := element.(types.Object); ok && object.Attributes()["name"].Equal(types.StringValue("mail")) {
+			mailRecord = path.Root("dns_records").AtSetValue(element)
+		}
+	}
+	paths, other := attributeErrorPaths(diags)
+	if len(paths) != 2 || !paths[0].Equal(mailRecord.AtName("data")) || !paths[1].Equal(path.Root("name")) {
+		t.Errorf("expected the errors on %s and name, got: %v", mailRecord.AtName("data"), paths)
+	}
+	if other != 2 {
+		t.Errorf("expected 2 errors without a path, got: %v", diags)
+	}
+	if detail := diags[0].Detail(); !strings.Contains(detail, "must be a quoted string") {
+		t.Errorf("expected the violation message in the detail, got: %s", detail)
+	}
+}
+
+func TestTrafficPatternFieldPath(t *testing.T) {
+	model := TrafficPatternResourceModel{
+		ContentType: types.StringValue("addr"),
+		Patterns: []*PatternsModel{
+			{Addr: types.StringValue("10.0.0.0/8")},
+			{Addr: types.StringValue("192.0.2.0/24"), Comment: types.StringValue("test")},
+		},
+	}
+	// Only the added pattern was sent.
+	sent := []*PatternsModel{{Addr: types.StringValue("192.0.2.0/24"), Comment: types.StringValue("test")}}
+
+	err := fmt.Errorf("could not add 1 patterns: %w", &APIError{StatusCode: http.StatusBadRequest, Violations: []APIFieldViolation{
+		{Field: "patterns[0].comment", Message: "is too long"},
+		{Field: "contentType", Message: "unsupported content type"},
+		{Field: "patterns[1].addr", Message: "is invalid"},
+	}})
+	var diags diag.Diagnostics
+	addAPIErrorDiagnostics(&diags, "Error Updating Ngenix Traffic Pattern", "Could not update Traffic Pattern patterns", err, model.fieldPath(sent))
+
+	paths, other := attributeErrorPaths(diags)
+	if len(paths) != 2 || !paths[0].Equal(path.Root("patterns").AtListIndex(1).AtName("comment")) || !paths[1].Equal(path.Root("content_type")) {
+		t.Errorf("expected the errors on patterns[1].comment and content_type, got: %v", paths)
+	}
+	if other != 1 {
+		t.Errorf("expected 1 error without a path, got: %v", diags)
+	}
+}
+
+func TestAddAPIErrorDiagnosticsWithoutViolations(t *testing.T) {
+	var diags diag.Diagnostics
+	addAPIErrorDiagnostics(&diags, "Error creating DNS zone", "Could not create DNS Zone, unexpected error", errors.New("connection reset"), nil)
+	if len(diags) != 1 || diags[0].Detail() != "Could not create DNS Zone, unexpected error: connection reset" {
+		t.Errorf("expected the error as is, got: %v", diags)
+	}
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Default timeouts of the resource operations, used if the timeouts block does not set them.
+const (
+	defaultCreateTimeout = 20 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 20 * time.Minute
+	defaultDeleteTimeout = 20 * time.Minute
+)
+
+// defaultRequestTimeout is used if the provider request_timeout attribute is not set.
+const defaultRequestTimeout = time.Minute
+
+// nullTimeouts returns the value of an absent timeouts block of the resources.
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{
+		Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		}),
+	}
+}
+
+// coreLibraryTransport sends the requests of the core library calls.
+// restapi.Client has no option to set the transport of its requests, so the
+// core library is assumed to send them with an http.Client without its own
+// transport, and coreLibraryTransport is installed as http.DefaultTransport.
+// If the assumption does not hold, the requests are sent as is: without the
+// context of the operation, retries, logging and the recorded API errors.
+var coreLibraryTransport = &callTransport{
+	calls: make(chan struct{}, 1),
+}
+
+// installCoreLibraryTransport installs coreLibraryTransport as http.DefaultTransport
+// once and returns the replaced default transport sending the requests.
+func installCoreLibraryTransport() http.RoundTripper {
+	coreLibraryTransport.install.Do(func() {
+		coreLibraryTransport.next = http.DefaultTransport
+		http.DefaultTransport = coreLibraryTransport
+	})
+	return coreLibraryTransport.next
+}
+
+// callTransport sends the requests created without a context, as the core
+// library does, with the context and the transport of the running core
+// library call. Nothing else tells the requests of concurrent calls apart,
+// so the calls are serialized: a call waits for the previous one to finish.
+type callTransport struct {
+	install sync.Once
+	next    http.RoundTripper
+	calls   chan struct{}
+
+	mu        sync.Mutex
+	ctx       context.Context
+	transport http.RoundTripper
+}
+
+// begin waits for the running call to finish and sends the requests with the
+// context and the transport until the returned release function is called.
+func (t *callTransport) begin(ctx context.Context, transport http.RoundTripper) (func(), error) {
+	select {
+	case t.calls <- struct{}{}:
+	case <-ctx.Done():
+		return nil, contextError(ctx, nil)
+	}
+	t.mu.Lock()
+	t.ctx, t.transport = ctx, transport
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		t.ctx, t.transport = nil, nil
+		t.mu.Unlock()
+		<-t.calls
+	}, nil
+}
+
+func (t *callTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	ctx, transport := t.ctx, t.transport
+	t.mu.Unlock()
+	if transport == nil {
+		// Not a request of a core library call.
+		return t.next.RoundTrip(req)
+	}
+	if req.Context().Done() == nil {
+		req = req.WithContext(ctx)
+	}
+	return transport.RoundTrip(req)
+}
+
+// timeoutTransport limits every attempt of a request with the provider
+// request_timeout, so a hung connection fails instead of hanging the apply.
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if t.timeout <= 0 {
+		return next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil {
+			return nil, fmt.Errorf("request timed out after %s, see the provider request_timeout: %w", t.timeout, err)
+		}
+		return nil, err
+	}
+	// The deadline also covers reading the body, it is released when the body is closed.
+	resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelReadCloser cancels the request context when the body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelReadCloser) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
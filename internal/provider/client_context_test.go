@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ngenix/restapi"
+)
+
+// newSlowServer starts a server responding after the delay.
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			_, _ = io.WriteString(w, `{"ok":true}`)
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	server := newSlowServer(t, 50*time.Millisecond)
+
+	client := &http.Client{Transport: &timeoutTransport{timeout: 10 * time.Millisecond}}
+	_, err := client.Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "request_timeout") {
+		t.Fatalf("expected the request timeout error, got: %v", err)
+	}
+
+	client = &http.Client{Transport: &timeoutTransport{timeout: time.Second}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	// The body is read after RoundTrip returned, within the request timeout.
+	if body, err := io.ReadAll(resp.Body); err != nil || string(body) != `{"ok":true}` {
+		t.Errorf("unexpected body %q, error: %v", body, err)
+	}
+}
+
+func TestCoreLibraryTransport(t *testing.T) {
+	server := newSlowServer(t, time.Second)
+	client := newNgenixClient(&restapi.Client{}, nil, nil)
+
+	// Requests created without a context by the default http.Client, as by the core library, get the context of the operation.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.call(ctx, "GetDnsZoneById", func(*restapi.Client) error {
+		_, err := http.Get(server.URL)
+		return err
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the operation timeout error, got: %v", err)
+	}
+
+	// The calls are serialized, a call waits for the running one within its operation timeout.
+	started, finish := make(chan struct{}), make(chan struct{})
+	go func() {
+		_ = client.call(context.Background(), "GetAllDnsZonesList", func(*restapi.Client) error {
+			close(started)
+			<-finish
+			return nil
+		})
+	}()
+	<-started
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	called := false
+	err = client.call(ctx, "GetDnsZoneById", func(*restapi.Client) error {
+		called = true
+		return nil
+	})
+	close(finish)
+	if called || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the call to time out waiting, got: %v", err)
+	}
+	if err := client.call(context.Background(), "GetDnsZoneById", func(*restapi.Client) error { return nil }); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile is the profile of the credentials file used if no profile is selected.
+const defaultProfile = "default"
+
+// ngenixCredentials are the Ngenix API credentials of the provider or of a profile.
+type ngenixCredentials struct {
+	Host     string `yaml:"host"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// merge returns the credentials with the empty values taken from the other ones.
+func (c ngenixCredentials) merge(other ngenixCredentials) ngenixCredentials {
+	if c.Host == "" {
+		c.Host = other.Host
+	}
+	if c.Username == "" {
+		c.Username = other.Username
+	}
+	if c.Password == "" {
+		c.Password = other.Password
+	}
+	return c
+}
+
+// defaultCredentialsFile returns the path of the credentials file used if
+// neither credentials_file nor NGENIX_CREDENTIALS_FILE is set.
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ngenix", "credentials")
+}
+
+// expandHome replaces the leading ~ of the path with the home directory.
+func expandHome(name string) string {
+	if name != "~" && !strings.HasPrefix(name, "~/") {
+		return name
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(home, strings.TrimPrefix(name, "~"))
+}
+
+// credentialsFromConfig resolves the credentials of the provider, every value
+// is taken from the first source which sets it:
+//  1. the host, username and password attributes;
+//  2. the profile selected by the profile attribute or NGENIX_PROFILE;
+//  3. the NGENIX_HOST, NGENIX_USERNAME and NGENIX_PASSWORD environment variables;
+//  4. the default profile of the credentials file, if the file exists.
+//
+// The credentials file is set by the credentials_file attribute or
+// NGENIX_CREDENTIALS_FILE, ~/.ngenix/credentials by default.
+func credentialsFromConfig(config ngenixProviderModel) (ngenixCredentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if config.Profile.IsUnknown() || config.CredentialsFile.IsUnknown() {
+		diags.AddError(
+			"Unknown Ngenix API Profile",
+			"The provider cannot create the Ngenix API client as there is an unknown configuration value for profile or credentials_file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, "+
+				"or use the NGENIX_PROFILE and NGENIX_CREDENTIALS_FILE environment variables.",
+		)
+		return ngenixCredentials{}, diags
+	}
+
+	credentials := ngenixCredentials{
+		Host:     config.Host.ValueString(),
+		Username: config.Username.ValueString(),
+		Password: config.Password.ValueString(),
+	}
+	environment := ngenixCredentials{
+		Host:     os.Getenv("NGENIX_HOST"),
+		Username: os.Getenv("NGENIX_USERNAME"),
+		Password: os.Getenv("NGENIX_PASSWORD"),
+	}
+
+	profile, profilePath := config.Profile.ValueString(), path.Root("profile")
+	if config.Profile.IsNull() {
+		profile, profilePath = os.Getenv("NGENIX_PROFILE"), path.Empty()
+	}
+	fileName, filePath := expandHome(config.CredentialsFile.ValueString()), path.Root("credentials_file")
+	if config.CredentialsFile.IsNull() {
+		fileName, filePath = os.Getenv("NGENIX_CREDENTIALS_FILE"), path.Empty()
+	}
+	explicitFile := fileName != ""
+	if !explicitFile {
+		fileName = defaultCredentialsFile()
+	}
+
+	// Without a selected profile the default one is optional, as is the default file.
+	if profile == "" && !explicitFile && fileName != "" {
+		if _, err := os.Stat(fileName); errors.Is(err, fs.ErrNotExist) {
+			return credentials.merge(environment), diags
+		}
+	}
+	if profile == "" && fileName == "" {
+		return credentials.merge(environment), diags
+	}
+
+	profiles, err := loadCredentialsFile(fileName)
+	if err != nil {
+		summary, detail := "Invalid Ngenix Credentials File", fmt.Sprintf("Could not read the credentials file %s, error: %s", fileName, err.Error())
+		if errors.Is(err, fs.ErrNotExist) {
+			summary = "Missing Ngenix Credentials File"
+			detail = fmt.Sprintf("The credentials file %s does not exist. Create it or set credentials_file or the NGENIX_CREDENTIALS_FILE environment variable.", fileName)
+		}
+		addProviderError(&diags, filePath, summary, detail)
+		return credentials, diags
+	}
+
+	if profile == "" {
+		return credentials.merge(environment).merge(profiles[defaultProfile]), diags
+	}
+	selected, ok := profiles[profile]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		addProviderError(&diags, profilePath, "Missing Ngenix Profile",
+			fmt.Sprintf("The profile %q is not found in the credentials file %s, the available profiles: %s. "+
+				"Set profile or the NGENIX_PROFILE environment variable to one of them.", profile, fileName, strings.Join(names, ", ")),
+		)
+		return credentials, diags
+	}
+	return credentials.merge(selected).merge(environment), diags
+}
+
+// addProviderError adds the error on the attribute, or without a path if the
+// value comes from an environment variable.
+func addProviderError(diags *diag.Diagnostics, attributePath path.Path, summary, detail string) {
+	if attributePath.Equal(path.Empty()) {
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddAttributeError(attributePath, summary, detail)
+}
+
+// loadCredentialsFile reads the profiles of the credentials file. The file is
+// parsed as INI if its first line, which is not a comment, is a [profile]
+// section header, and as YAML mapping the profile names to the credentials otherwise.
+func loadCredentialsFile(name string) (map[string]ngenixCredentials, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if isINI(data) {
+		return parseINICredentials(data)
+	}
+	return parseYAMLCredentials(data)
+}
+
+// isINI reports whether the first significant line of the file is a section header.
+func isINI(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		return strings.HasPrefix(line, "[")
+	}
+	return false
+}
+
+// parseINICredentials parses the profiles of the INI credentials file:
+//
+//	[default]
+//	host     = https://api.ngenix.net/api/v3/
+//	username = user@example.com/customer
+//	password = token
+func parseINICredentials(data []byte) (map[string]ngenixCredentials, error) {
+	profiles := map[string]ngenixCredentials{}
+	profile := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") || strings.TrimSpace(line[1:len(line)-1]) == "" {
+				return nil, fmt.Errorf("line %d: invalid profile header %s", lineNumber, line)
+			}
+			profile = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := profiles[profile]; ok {
+				return nil, fmt.Errorf("line %d: duplicate profile %s", lineNumber, profile)
+			}
+			profiles[profile] = ngenixCredentials{}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		if profile == "" {
+			return nil, fmt.Errorf("line %d: %s is set outside of a profile", lineNumber, strings.TrimSpace(key))
+		}
+		credentials := profiles[profile]
+		if err := credentials.set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		profiles[profile] = credentials
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// set sets the value of the credentials file key.
+func (c *ngenixCredentials) set(key, value string) error {
+	switch key {
+	case "host":
+		c.Host = value
+	case "username":
+		c.Username = value
+	case "password":
+		c.Password = value
+	default:
+		return fmt.Errorf("unknown key %s, expected host, username or password", key)
+	}
+	return nil
+}
+
+// parseYAMLCredentials parses the profiles of the YAML credentials file:
+//
+//	default:
+//	  host: https://api.ngenix.net/api/v3/
+//	  username: user@example.com/customer
+//	  password: token
+func parseYAMLCredentials(data []byte) (map[string]ngenixCredentials, error) {
+	profiles := map[string]ngenixCredentials{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testINICredentials = `# Ngenix API credentials
+[default]
+host     = https://default.example.com/api/v3/
+username = default@example.com/1
+password = default-token
+
+; the staging customer
+[staging]
+host     = https://staging.example.com/api/v3/
+username = staging@example.com/2
+password = staging = token
+`
+
+const testYAMLCredentials = `default:
+  host: https://default.example.com/api/v3/
+  username: default@example.com/1
+  password: default-token
+staging:
+  host: https://staging.example.com/api/v3/
+  username: staging@example.com/2
+  password: staging = token
+`
+
+func TestLoadCredentialsFile(t *testing.T) {
+	expected := map[string]ngenixCredentials{
+		"default": {Host: "https://default.example.com/api/v3/", Username: "default@example.com/1", Password: "default-token"},
+		"staging": {Host: "https://staging.example.com/api/v3/", Username: "staging@example.com/2", Password: "staging = token"},
+	}
+	for name, data := range map[string]string{"ini": testINICredentials, "yaml": testYAMLCredentials} {
+		t.Run(name, func(t *testing.T) {
+			profiles, err := loadCredentialsFile(writeCredentialsFile(t, data))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(profiles) != len(expected) || profiles["default"] != expected["default"] || profiles["staging"] != expected["staging"] {
+				t.Errorf("expected %v, got %v", expected, profiles)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		err  string
+	}{
+		"ini unknown key":       {data: "[default]\ntoken = secret\n", err: "line 2: unknown key token"},
+		"ini duplicate profile": {data: "[default]\n[staging]\n[default]\n", err: "line 3: duplicate profile default"},
+		"ini invalid header":    {data: "[default\n", err: "line 1: invalid profile header"},
+		"ini missing value":     {data: "[default]\nhost\n", err: "line 2: expected key = value"},
+		"yaml unknown key":      {data: "default:\n  token: secret\n", err: "field token not found"},
+		"yaml not a mapping":    {data: "- default\n", err: "cannot unmarshal"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadCredentialsFile(writeCredentialsFile(t, test.data))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected the error %q, got: %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestCredentialsFromConfig(t *testing.T) {
+	file := writeCredentialsFile(t, testINICredentials)
+	environment := ngenixCredentials{Host: "https://env.example.com/api/v3/", Username: "env@example.com/3", Password: "env-token"}
+
+	tests := map[string]struct {
+		config      ngenixProviderModel
+		env         map[string]string
+		credentials ngenixCredentials
+	}{
+		"default profile": {
+			config:      ngenixProviderModel{CredentialsFile: types.StringValue(file)},
+			credentials: ngenixCredentials{Host: "https://default.example.com/api/v3/", Username: "default@example.com/1", Password: "default-token"},
+		},
+		"environment over default profile": {
+			config:      ngenixProviderModel{CredentialsFile: types.StringValue(file)},
+			env:         map[string]string{"NGENIX_USERNAME": environment.Username, "NGENIX_PASSWORD": environment.Password},
+			credentials: ngenixCredentials{Host: "https://default.example.com/api/v3/", Username: environment.Username, Password: environment.Password},
+		},
+		"selected profile over environment": {
+			config:      ngenixProviderModel{Profile: types.StringValue("staging"), CredentialsFile: types.StringValue(file)},
+			env:         map[string]string{"NGENIX_HOST": environment.Host, "NGENIX_PASSWORD": environment.Password},
+			credentials: ngenixCredentials{Host: "https://staging.example.com/api/v3/", Username: "staging@example.com/2", Password: "staging = token"},
+		},
+		"attributes over selected profile": {
+			config:      ngenixProviderModel{Password: types.StringValue("attribute-token")},
+			env:         map[string]string{"NGENIX_PROFILE": "staging", "NGENIX_CREDENTIALS_FILE": file},
+			credentials: ngenixCredentials{Host: "https://staging.example.com/api/v3/", Username: "staging@example.com/2", Password: "attribute-token"},
+		},
+		"profile attribute over environment": {
+			config:      ngenixProviderModel{Profile: types.StringValue("default")},
+			env:         map[string]string{"NGENIX_PROFILE": "staging", "NGENIX_CREDENTIALS_FILE": file},
+			credentials: ngenixCredentials{Host: "https://default.example.com/api/v3/", Username: "default@example.com/1", Password: "default-token"},
+		},
+		"no default credentials file": {
+			config:      ngenixProviderModel{Host: types.StringValue("https://attribute.example.com/api/v3/")},
+			env:         map[string]string{"NGENIX_USERNAME": environment.Username, "NGENIX_PASSWORD": environment.Password},
+			credentials: ngenixCredentials{Host: "https://attribute.example.com/api/v3/", Username: environment.Username, Password: environment.Password},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setCredentialsEnv(t, test.env)
+			credentials, diags := credentialsFromConfig(test.config)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if credentials != test.credentials {
+				t.Errorf("expected %+v, got %+v", test.credentials, credentials)
+			}
+		})
+	}
+}
+
+func TestCredentialsFromConfigHomeFile(t *testing.T) {
+	setCredentialsEnv(t, nil)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".ngenix"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".ngenix", "credentials"), []byte(testYAMLCredentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	credentials, diags := credentialsFromConfig(ngenixProviderModel{Profile: types.StringValue("staging")})
+	if diags.HasError() || credentials.Host != "https://staging.example.com/api/v3/" {
+		t.Errorf("expected the staging profile of ~/.ngenix/credentials, got %+v, %v", credentials, diags)
+	}
+	credentials, diags = credentialsFromConfig(ngenixProviderModel{CredentialsFile: types.StringValue("~/.ngenix/credentials")})
+	if diags.HasError() || credentials.Host != "https://default.example.com/api/v3/" {
+		t.Errorf("expected the default profile of ~/.ngenix/credentials, got %+v, %v", credentials, diags)
+	}
+}
+
+func TestCredentialsFromConfigErrors(t *testing.T) {
+	file := writeCredentialsFile(t, testINICredentials)
+	missingFile := filepath.Join(t.TempDir(), "credentials")
+
+	tests := map[string]struct {
+		config  ngenixProviderModel
+		env     map[string]string
+		summary string
+		path    path.Path
+		detail  string
+	}{
+		"missing profile": {
+			config:  ngenixProviderModel{Profile: types.StringValue("production"), CredentialsFile: types.StringValue(file)},
+			summary: "Missing Ngenix Profile",
+			path:    path.Root("profile"),
+			detail:  `The profile "production" is not found in the credentials file ` + file + `, the available profiles: default, staging.`,
+		},
+		"missing profile from environment": {
+			env:     map[string]string{"NGENIX_PROFILE": "production", "NGENIX_CREDENTIALS_FILE": file},
+			summary: "Missing Ngenix Profile",
+			detail:  `The profile "production" is not found`,
+		},
+		"missing credentials file": {
+			config:  ngenixProviderModel{CredentialsFile: types.StringValue(missingFile)},
+			summary: "Missing Ngenix Credentials File",
+			path:    path.Root("credentials_file"),
+			detail:  "The credentials file " + missingFile + " does not exist.",
+		},
+		"missing default credentials file with profile": {
+			config:  ngenixProviderModel{Profile: types.StringValue("staging")},
+			env:     map[string]string{"HOME": t.TempDir()},
+			summary: "Missing Ngenix Credentials File",
+			detail:  "does not exist",
+		},
+		"invalid credentials file": {
+			config:  ngenixProviderModel{CredentialsFile: types.StringValue(writeCredentialsFile(t, "[default]\ntoken = secret\n"))},
+			summary: "Invalid Ngenix Credentials File",
+			path:    path.Root("credentials_file"),
+			detail:  "line 2: unknown key token",
+		},
+		"unknown profile": {
+			config:  ngenixProviderModel{Profile: types.StringUnknown()},
+			summary: "Unknown Ngenix API Profile",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setCredentialsEnv(t, test.env)
+			_, diags := credentialsFromConfig(test.config)
+			if len(diags) != 1 || diags[0].Summary() != test.summary || !strings.Contains(diags[0].Detail(), test.detail) {
+				t.Fatalf("expected the error %q with %q, got: %v", test.summary, test.detail, diags)
+			}
+			var attributePath path.Path
+			if withPath, ok := diags[0].(diag.DiagnosticWithPath); ok {
+				attributePath = withPath.Path()
+			}
+			if !attributePath.Equal(test.path) {
+				t.Errorf("expected the error on %q, got %q", test.path, attributePath)
+			}
+		})
+	}
+}
+
+// writeCredentialsFile writes the credentials file to a temporary directory and returns its path.
+func writeCredentialsFile(t *testing.T, data string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+// setCredentialsEnv clears the Ngenix environment variables and points HOME
+// to an empty directory, then sets the given variables for the test.
+func setCredentialsEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	for _, key := range []string{"NGENIX_HOST", "NGENIX_USERNAME", "NGENIX_PASSWORD", "NGENIX_PROFILE", "NGENIX_CREDENTIALS_FILE"} {
+		t.Setenv(key, "")
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
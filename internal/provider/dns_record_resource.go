@@ -0,0 +1,608 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &dnsRecordResource{}
+	_ resource.ResourceWithConfigure      = &dnsRecordResource{}
+	_ resource.ResourceWithImportState    = &dnsRecordResource{}
+	_ resource.ResourceWithValidateConfig = &dnsRecordResource{}
+)
+
+// DnsRecordResource is a helper function to simplify the provider implementation.
+func DnsRecordResource() resource.Resource {
+	return &dnsRecordResource{}
+}
+
+// dnsRecordResource is the resource implementation.
+type dnsRecordResource struct {
+	client *ngenixClient
+}
+
+// dnsRecordResourceModel maps the resource schema data.
+type dnsRecordResourceModel struct {
+	ID             types.String             `tfsdk:"id"`
+	ZoneID         types.String             `tfsdk:"zone_id"`
+	Name           types.String             `tfsdk:"name"`
+	Type           types.String             `tfsdk:"type"`
+	Data           types.String             `tfsdk:"data"`
+	Priority       types.Int64              `tfsdk:"priority"`
+	Weight         types.Int64              `tfsdk:"weight"`
+	Port           types.Int64              `tfsdk:"port"`
+	Flags          types.Int64              `tfsdk:"flags"`
+	Tag            types.String             `tfsdk:"tag"`
+	ConfigRef      *configRefItemModel      `tfsdk:"config_ref"`
+	TargetGroupRef *targetGroupRefItemModel `tfsdk:"targetgroup_ref"`
+	LastUpdated    types.String             `tfsdk:"last_updated"`
+}
+
+// recordItem returns the DNS record in the form used by the DNS zone resource.
+func (m *dnsRecordResourceModel) recordItem() dnsRecordsItemModel {
+	return dnsRecordsItemModel{
+		Name:           m.Name,
+		Type:           m.Type,
+		Data:           m.Data,
+		Priority:       m.Priority,
+		Weight:         m.Weight,
+		Port:           m.Port,
+		Flags:          m.Flags,
+		Tag:            m.Tag,
+		ConfigRef:      m.ConfigRef,
+		TargetGroupRef: m.TargetGroupRef,
+	}
+}
+
+// key returns the key of the DNS record of the state. The imported state has
+// no record values yet, so its key is taken from the ID.
+func (m *dnsRecordResourceModel) key() (dnsRecordKey, error) {
+	if m.Data.IsNull() && m.ConfigRef == nil && m.TargetGroupRef == nil {
+		_, key, err := parseDnsRecordID(m.ID.ValueString())
+		return key, err
+	}
+	records, err := DNSRecordsModelTransformation([]dnsRecordsItemModel{m.recordItem()})
+	if err != nil {
+		return dnsRecordKey{}, err
+	}
+	return newDnsRecordKey(records[0]), nil
+}
+
+// setRecord copies the DNS record values read from the API to the model,
+// keeping the current form of the record if it is equal to the API one.
+func (m *dnsRecordResourceModel) setRecord(record restapi.Records) error {
+	items, err := DNSRecordsItemModelTransformation([]restapi.Records{record})
+	if err != nil {
+		return err
+	}
+	item := preferConfiguredDnsRecords(items, []dnsRecordsItemModel{m.recordItem()})[0]
+	m.Name = item.Name
+	m.Type = item.Type
+	m.Data = item.Data
+	m.Priority = item.Priority
+	m.Weight = item.Weight
+	m.Port = item.Port
+	m.Flags = item.Flags
+	m.Tag = item.Tag
+	m.ConfigRef = item.ConfigRef
+	m.TargetGroupRef = item.TargetGroupRef
+	m.ID = types.StringValue(m.ZoneID.ValueString() + "/" + newDnsRecordKey(record).String())
+	return nil
+}
+
+// dnsRecordKey identifies a DNS record within the zone. The records of one
+// RRset share the name and type, so the value of the record is a part of the
+// key: the data, or the reference of an A record, e.g. config_ref:88903.
+type dnsRecordKey struct {
+	name       string
+	recordType string
+	value      string
+}
+
+func newDnsRecordKey(record restapi.Records) dnsRecordKey {
+	value := record.Data
+	if record.ConfigRef != nil {
+		value = fmt.Sprintf("config_ref:%d", record.ConfigRef.ID)
+	} else if record.TargetGroupRef != nil {
+		value = fmt.Sprintf("targetgroup_ref:%d", record.TargetGroupRef.ID)
+	}
+	return dnsRecordKey{name: record.Name, recordType: record.Type, value: value}
+}
+
+// String returns the key in the name/type/value format of the resource ID.
+func (k dnsRecordKey) String() string {
+	return k.name + "/" + k.recordType + "/" + k.value
+}
+
+// parseDnsRecordID parses the zone_id/name/type/value identifier of the DNS
+// record, the value may contain slashes.
+func parseDnsRecordID(id string) (string, dnsRecordKey, error) {
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", dnsRecordKey{}, fmt.Errorf("expected DNS record identifier with format: zone_id/name/type/value. Got: %q", id)
+	}
+	return parts[0], dnsRecordKey{name: parts[1], recordType: parts[2], value: parts[3]}, nil
+}
+
+// findDnsRecord returns the index of the record with the key, or -1.
+func findDnsRecord(records []restapi.Records, key dnsRecordKey) int {
+	for i, record := range records {
+		if newDnsRecordKey(record) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// findDnsCnameConflict checks the record against the records of the zone with
+// the same name, except the one at the skipped index, which the record replaces.
+func findDnsCnameConflict(records []restapi.Records, skip int, name, recordType string) error {
+	for i, zoneRecord := range records {
+		if i == skip || !sameDnsName(zoneRecord.Name, name) {
+			continue
+		}
+		if err := dnsCnameConflict(name, recordType, zoneRecord.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *dnsRecordResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ngenixClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *dnsRecordResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+// Schema defines the schema for the resource.
+func (r *dnsRecordResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	recordAttributes := dnsRecordsNestedObject().Attributes
+	resp.Schema = schema.Schema{
+		Description: "Manages a single DNS record within a DNS zone, other records of the zone are left untouched. " +
+			"A record is identified by its name, type and value within the zone, so every record of a multi-value RRset is a separate resource. " +
+			"Every change reads the zone and writes its records back whole, the changes of records of one zone are serialized within a single Terraform run only, " +
+			"so concurrent applies of separate configurations managing records of the same zone could overwrite each other." +
+			" The TTL of the records cannot be set, the Ngenix core library has no TTL for DNS records.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				Description: "DNS record identifier in format zone_id/name/type/value, the value is the record data as stored by Ngenix, " +
+					"or config_ref:<id> and targetgroup_ref:<id> for A records with a reference.",
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of the last Terraform update of the DNS record.",
+			},
+			"zone_id": schema.StringAttribute{
+				Required:    true,
+				Description: "DNS zone ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "DNS record name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "DNS record type",
+				Validators: []validator.String{
+					stringvalidator.OneOf(DnsRecordTypes...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"data":            recordAttributes["data"],
+			"priority":        recordAttributes["priority"],
+			"weight":          recordAttributes["weight"],
+			"port":            recordAttributes["port"],
+			"flags":           recordAttributes["flags"],
+			"tag":             recordAttributes["tag"],
+			"config_ref":      recordAttributes["config_ref"],
+			"targetgroup_ref": recordAttributes["targetgroup_ref"],
+		},
+	}
+}
+
+// ValidateConfig checks the DNS record at plan time. Conflicts with other
+// records of the zone are checked on create, when the zone records are known.
+func (r *dnsRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// Records with unknown references are checked once the values are known.
+	for _, name := range []string{"config_ref", "targetgroup_ref"} {
+		var ref types.Object
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &ref)...)
+		if resp.Diagnostics.HasError() || ref.IsUnknown() {
+			return
+		}
+	}
+
+	var config dnsRecordResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	record := config.recordItem()
+	for _, recordErr := range validateDnsRecord(record, "") {
+		addDnsRecordError(&resp.Diagnostics, path.Empty(), record, recordErr)
+	}
+}
+
+// Create adds the DNS record to the zone and sets the initial Terraform state.
+func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan.
+	var plan dnsRecordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneId, err := dnsZoneID(plan.ZoneID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("zone_id"), "Error creating DNS record", err.Error())
+		return
+	}
+
+	// Generate API request body from plan.
+	records, err := DNSRecordsModelTransformation([]dnsRecordsItemModel{plan.recordItem()})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS record creation and validation process",
+			fmt.Sprintf("Could not create DNS record, error: %s", err.Error()),
+		)
+		return
+	}
+
+	unlock := lockDnsZone(zoneId)
+	defer unlock()
+
+	dnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Ngenix DNS zone by ID",
+			fmt.Sprintf("Could not read Ngenix DNS zone by ID %d, error: %s", zoneId, err.Error()),
+		)
+		return
+	}
+	key := newDnsRecordKey(records[0])
+	if findDnsRecord(dnsZone.Records, key) >= 0 {
+		resp.Diagnostics.AddError(
+			"DNS record has already exist!",
+			fmt.Sprintf("Could not create DNS record - %s record %s with value %s has already exist in DNS zone %d, import it instead.",
+				key.recordType, key.name, key.value, zoneId),
+		)
+		return
+	}
+	if err := findDnsCnameConflict(dnsZone.Records, -1, plan.Name.ValueString(), plan.Type.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid DNS record",
+			fmt.Sprintf("Could not create DNS record in DNS zone %d, error: %s", zoneId, err.Error()),
+		)
+		return
+	}
+
+	record, diags := r.updateRecords(ctx, zoneId, dnsZone.Comment, append(dnsZone.Records, records[0]), key)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := plan.setRecord(record); err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS record creation and validation process",
+			fmt.Sprintf("Could not read DNS record, error: %s", err.Error()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	tflog.Trace(ctx, "DNS record was created successfully!")
+
+	// Set state to fully populated data.
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state.
+	var state dnsRecordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneId, err := dnsZoneID(state.ZoneID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Ngenix DNS record", err.Error())
+		return
+	}
+	key, err := state.key()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Ngenix DNS record", err.Error())
+		return
+	}
+
+	dnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+	if isNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Ngenix DNS zone not found",
+			fmt.Sprintf("DNS Zone with ID = %d was not found in Ngenix, DNS record %s is removed from the state.", zoneId, state.ID.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix DNS zone",
+			fmt.Sprintf("Could not read Ngenix DNS Zone by ID = %d, error: %s", zoneId, err.Error()),
+		)
+		return
+	}
+
+	index := findDnsRecord(dnsZone.Records, key)
+	if index < 0 {
+		// DNS record was deleted outside of Terraform, let Terraform re-create it.
+		resp.Diagnostics.AddWarning(
+			"Ngenix DNS record not found",
+			fmt.Sprintf("DNS record %s was not found in Ngenix and is removed from the state, it will be re-created on the next apply.", state.ID.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err := state.setRecord(dnsZone.Records[index]); err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS record creation and validation process",
+			fmt.Sprintf("Could not read DNS record, error: %s", err.Error()),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "DNS record was read successfully!")
+
+	// Set refreshed state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update replaces the DNS record in the zone and sets the updated Terraform state on success.
+func (r *dnsRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and state.
+	var plan, state dnsRecordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	stateKey, err := state.key()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Ngenix DNS record", err.Error())
+		return
+	}
+
+	zoneId, err := dnsZoneID(plan.ZoneID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Ngenix DNS record", err.Error())
+		return
+	}
+
+	// Generate API request body from plan.
+	records, err := DNSRecordsModelTransformation([]dnsRecordsItemModel{plan.recordItem()})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS record creation and validation process",
+			fmt.Sprintf("Could not update DNS record, error: %s", err.Error()),
+		)
+		return
+	}
+
+	unlock := lockDnsZone(zoneId)
+	defer unlock()
+
+	dnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Ngenix DNS zone by ID",
+			fmt.Sprintf("Could not read Ngenix DNS zone by ID %d, error: %s", zoneId, err.Error()),
+		)
+		return
+	}
+	index := findDnsRecord(dnsZone.Records, stateKey)
+	if index < 0 {
+		resp.Diagnostics.AddError(
+			"Ngenix DNS record not found",
+			fmt.Sprintf("Could not update DNS record %s, it does not exist in Ngenix anymore. Refresh the state to re-create it.", state.ID.ValueString()),
+		)
+		return
+	}
+	key := newDnsRecordKey(records[0])
+	if existing := findDnsRecord(dnsZone.Records, key); existing >= 0 && existing != index {
+		resp.Diagnostics.AddError(
+			"DNS record has already exist!",
+			fmt.Sprintf("Could not update DNS record - %s record %s with value %s has already exist in DNS zone %d.",
+				key.recordType, key.name, key.value, zoneId),
+		)
+		return
+	}
+	if err := findDnsCnameConflict(dnsZone.Records, index, plan.Name.ValueString(), plan.Type.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid DNS record",
+			fmt.Sprintf("Could not update DNS record in DNS zone %d, error: %s", zoneId, err.Error()),
+		)
+		return
+	}
+	updatedRecords := append([]restapi.Records{}, dnsZone.Records...)
+	updatedRecords[index] = records[0]
+
+	record, diags := r.updateRecords(ctx, zoneId, dnsZone.Comment, updatedRecords, key)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := plan.setRecord(record); err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS record creation and validation process",
+			fmt.Sprintf("Could not read DNS record, error: %s", err.Error()),
+		)
+		return
+	}
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	tflog.Trace(ctx, "DNS record was updated successfully!")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the DNS record from the zone and the Terraform state on success.
+func (r *dnsRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state.
+	var state dnsRecordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zoneId, err := dnsZoneID(state.ZoneID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Ngenix DNS record", err.Error())
+		return
+	}
+	key, err := state.key()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Ngenix DNS record", err.Error())
+		return
+	}
+
+	unlock := lockDnsZone(zoneId)
+	defer unlock()
+
+	dnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+	if isNotFound(err) {
+		// Records are gone together with the DNS zone.
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Ngenix DNS zone by ID",
+			fmt.Sprintf("Could not read Ngenix DNS zone by ID %d, error: %s", zoneId, err.Error()),
+		)
+		return
+	}
+	index := findDnsRecord(dnsZone.Records, key)
+	if index < 0 {
+		return
+	}
+	updatedRecords := append(append([]restapi.Records{}, dnsZone.Records[:index]...), dnsZone.Records[index+1:]...)
+
+	_, err = r.client.UpdateDnsZone(ctx, zoneId, restapi.DnsZone{
+		Records: updatedRecords,
+		Comment: dnsZone.Comment,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting Ngenix DNS record",
+			fmt.Sprintf("Could not delete DNS record %s (PATCH), unexpected error: %s", state.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "DNS record was deleted successfully!")
+}
+
+// ImportState imports a DNS record by zone_id/name/type/value.
+func (r *dnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	zoneId, key, err := parseDnsRecordID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", err.Error())
+		return
+	}
+
+	// Read fills in the rest of the DNS record attributes.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("zone_id"), zoneId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), key.name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), key.recordType)...)
+}
+
+// updateRecords writes the full record set of the DNS zone and returns the
+// resulting record with the key.
+func (r *dnsRecordResource) updateRecords(ctx context.Context, zoneId int, comment string, records []restapi.Records, key dnsRecordKey) (restapi.Records, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	_, err := r.client.UpdateDnsZone(ctx, zoneId, restapi.DnsZone{
+		Records: records,
+		Comment: comment,
+	})
+	if err != nil {
+		// Only the violations of this record are shown on its attributes.
+		recordIndex := findDnsRecord(records, key)
+		addAPIErrorDiagnostics(&diags, "Error Updating Ngenix DNS zones", "Could not update DNS Zone (PATCH), unexpected error", err,
+			func(field apiField) (path.Path, bool) {
+				if field.name != "records" || field.index < 0 || field.index != recordIndex || field.attribute == "" {
+					return path.Empty(), false
+				}
+				return path.Root(attributeName(field.attribute)), true
+			})
+		return restapi.Records{}, diags
+	}
+
+	updatedDnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+	if err != nil {
+		diags.AddError(
+			"Error reading Ngenix DNS zone by ID",
+			fmt.Sprintf("Could not read Ngenix DNS zone by ID %d, error: %s", zoneId, err.Error()),
+		)
+		return restapi.Records{}, diags
+	}
+	index := findDnsRecord(updatedDnsZone.Records, key)
+	if index < 0 {
+		diags.AddError(
+			"Ngenix DNS record not found",
+			fmt.Sprintf("DNS record %s %s with value %s is missing in DNS zone %d after update.", key.recordType, key.name, key.value, zoneId),
+		)
+		return restapi.Records{}, diags
+	}
+	return updatedDnsZone.Records[index], diags
+}
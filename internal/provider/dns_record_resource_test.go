@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDnsRecordResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformrecordtest.ru"
+  ignore_unmanaged_records = true
+  dns_records = [
+    {
+      name = "vm-a-record"
+      type = "A"
+      data = "23.12.76.128"
+    }
+  ]
+}
+
+resource "ngenix_dns_record" "test" {
+  zone_id = ngenix_dnszone.test.id
+  name    = "team-cname"
+  type    = "CNAME"
+  data    = "team.example.com."
+}
+
+# Another record of the RRset of the DNS zone record.
+resource "ngenix_dns_record" "rrset" {
+  zone_id = ngenix_dnszone.test.id
+  name    = "vm-a-record"
+  type    = "A"
+  data    = "23.12.76.129"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify the DNS record.
+					resource.TestCheckResourceAttr("ngenix_dns_record.test", "name", "team-cname"),
+					resource.TestCheckResourceAttr("ngenix_dns_record.test", "type", "CNAME"),
+					resource.TestCheckResourceAttr("ngenix_dns_record.test", "data", "team.example.com."),
+					resource.TestCheckResourceAttrPair("ngenix_dns_record.test", "zone_id", "ngenix_dnszone.test", "id"),
+					resource.TestCheckResourceAttr("ngenix_dns_record.rrset", "data", "23.12.76.129"),
+					// Verify the DNS zone keeps only its own records.
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "1"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("ngenix_dns_record.test", "id"),
+					resource.TestCheckResourceAttrSet("ngenix_dns_record.test", "last_updated"),
+				),
+			},
+			// ImportState testing.
+			{
+				ResourceName:      "ngenix_dns_record.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The last_updated attribute does not exist in the Ngenix
+				// API, therefore there is no value for it during import.
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:            "ngenix_dns_record.rrset",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing, the DNS zone records are changed at the same time.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformrecordtest.ru"
+  ignore_unmanaged_records = true
+  dns_records = [
+    {
+      name = "vm-a-record"
+      type = "A"
+      data = "23.12.76.128"
+    },
+    {
+      name = "config-ref-88903"
+      type = "A"
+      config_ref = {
+        id = 88903
+      }
+    }
+  ]
+}
+
+resource "ngenix_dns_record" "test" {
+  zone_id = ngenix_dnszone.test.id
+  name    = "team-cname"
+  type    = "CNAME"
+  data    = "team-v2.example.com."
+}
+
+resource "ngenix_dns_record" "rrset" {
+  zone_id = ngenix_dnszone.test.id
+  name    = "vm-a-record"
+  type    = "A"
+  data    = "23.12.76.130"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_dns_record.test", "data", "team-v2.example.com."),
+					resource.TestCheckResourceAttr("ngenix_dns_record.rrset", "data", "23.12.76.130"),
+					resource.TestMatchResourceAttr("ngenix_dns_record.rrset", "id", regexp.MustCompile(`^\d+/vm-a-record/A/23\.12\.76\.130$`)),
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase.
+		},
+	})
+}
+
+func TestDnsRecordKey(t *testing.T) {
+	records := []restapi.Records{
+		{Name: "www", Type: "A", Data: "192.0.2.10"},
+		{Name: "www", Type: "A", Data: "192.0.2.11"},
+		{Name: "www", Type: "A", ConfigRef: &restapi.ConfigRef{ID: 88903}},
+		{Name: "@", Type: "TXT", Data: "v=DKIM1; p=MIGf/MA0GCS+qGSIb3"},
+	}
+	for i, record := range records {
+		if index := findDnsRecord(records, newDnsRecordKey(record)); index != i {
+			t.Errorf("expected record %d to be found by its key, got: %d", i, index)
+		}
+	}
+
+	zoneId, key, err := parseDnsRecordID("6184123/@/TXT/v=DKIM1; p=MIGf/MA0GCS+qGSIb3")
+	if err != nil || zoneId != "6184123" || findDnsRecord(records, key) != 3 {
+		t.Errorf("expected the TXT record with slashes in its data, got: %s, %+v, %v", zoneId, key, err)
+	}
+	if _, key, err := parseDnsRecordID("6184123/www/A/config_ref:88903"); err != nil || findDnsRecord(records, key) != 2 {
+		t.Errorf("expected the A record with the config reference, got: %+v, %v", key, err)
+	}
+	if _, _, err := parseDnsRecordID("6184123/www/A"); err == nil {
+		t.Error("expected an error for the identifier without the value")
+	}
+}
+
+func TestFindDnsCnameConflict(t *testing.T) {
+	records := []restapi.Records{
+		{Name: "www", Type: "A", Data: "192.0.2.10"},
+		{Name: "alias", Type: "CNAME", Data: "www.example.com."},
+	}
+	if err := findDnsCnameConflict(records, -1, "WWW.", "A"); err != nil {
+		t.Errorf("expected no conflict of A records, got: %s", err)
+	}
+	if err := findDnsCnameConflict(records, -1, "www", "CNAME"); err == nil {
+		t.Error("expected the CNAME record to conflict with the A record")
+	}
+	if err := findDnsCnameConflict(records, -1, "alias", "TXT"); err == nil {
+		t.Error("expected the TXT record to conflict with the CNAME record")
+	}
+	// The updated record does not conflict with itself.
+	if err := findDnsCnameConflict(records, 1, "alias", "CNAME"); err != nil {
+		t.Errorf("expected no conflict of the replaced record, got: %s", err)
+	}
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dnsLabelRegexp matches a single label of a DNS name. Underscores are allowed
+// for service labels like _sip._tcp.
+var dnsLabelRegexp = regexp.MustCompile(`^([A-Za-z0-9_]|[A-Za-z0-9_][A-Za-z0-9_-]{0,61}[A-Za-z0-9_])$`)
+
+// dnsTxtStringMaxLength is the maximum length of a single TXT character string in bytes.
+const dnsTxtStringMaxLength = 255
+
+// dnsRecordError is a DNS record validation error. Attribute names the record
+// attribute the error belongs to, or is empty if the error is about the whole record.
+type dnsRecordError struct {
+	Attribute string
+	Err       error
+}
+
+// validateDnsRecord checks the DNS record values against the record type.
+// Values which are unknown at plan time are skipped, they are checked again on apply.
+// The zone name is used to detect records at the zone apex and could be empty.
+func validateDnsRecord(record dnsRecordsItemModel, zoneName string) []dnsRecordError {
+	recordErrors := []dnsRecordError{}
+
+	if !record.Name.IsUnknown() {
+		if err := validateDnsRecordName(record.Name.ValueString()); err != nil {
+			recordErrors = append(recordErrors, dnsRecordError{Attribute: "name", Err: err})
+		}
+		// Requirement: CNAME record could not be placed at the zone apex together with SOA and NS records.
+		if record.Type.ValueString() == "CNAME" && isDnsZoneApex(record.Name.ValueString(), zoneName) {
+			recordErrors = append(recordErrors, dnsRecordError{
+				Attribute: "name",
+				Err:       errors.New("CNAME record is not allowed at the zone apex"),
+			})
+		}
+	}
+
+	for _, value := range []interface{ IsUnknown() bool }{
+		record.Type, record.Data, record.Priority, record.Weight, record.Port, record.Flags, record.Tag,
+	} {
+		if value.IsUnknown() {
+			return recordErrors
+		}
+	}
+
+	apiRecords, err := DNSRecordsModelTransformation([]dnsRecordsItemModel{record})
+	if err != nil {
+		return append(recordErrors, dnsRecordError{Err: err})
+	}
+	apiRecord := apiRecords[0]
+	// A records pointing to a config or a target group do not have data.
+	if apiRecord.ConfigRef != nil || apiRecord.TargetGroupRef != nil {
+		return recordErrors
+	}
+	if err := validateDnsRecordData(apiRecord.Type, apiRecord.Data); err != nil {
+		recordErrors = append(recordErrors, dnsRecordError{Attribute: "data", Err: err})
+	}
+	return recordErrors
+}
+
+// validateDnsRecordData checks the record data in the zone file presentation format.
+func validateDnsRecordData(recordType, data string) error {
+	switch recordType {
+	case "A":
+		addr, err := netip.ParseAddr(data)
+		if err != nil || !addr.Is4() {
+			return fmt.Errorf("%q is not a valid IPv4 address", data)
+		}
+	case "AAAA":
+		addr, err := netip.ParseAddr(data)
+		if err != nil || !addr.Is6() || addr.Is4In6() || addr.Zone() != "" {
+			return fmt.Errorf("%q is not a valid IPv6 address", data)
+		}
+	case "CNAME", "NS":
+		return validateDnsHostname(data)
+	case "MX":
+		fields := strings.Fields(data)
+		if len(fields) != 2 {
+			return fmt.Errorf("MX record data %q should be in format \"<priority> <host>\"", data)
+		}
+		if _, err := strconv.ParseUint(fields[0], 10, 16); err != nil {
+			return fmt.Errorf("MX record priority %q should be in range [0, 65535]", fields[0])
+		}
+		// A single dot is the null MX record of a domain which does not accept mail.
+		if fields[1] != "." {
+			return validateDnsHostname(fields[1])
+		}
+	case "SRV":
+		fields := strings.Fields(data)
+		if len(fields) != 4 {
+			return fmt.Errorf("SRV record data %q should be in format \"<priority> <weight> <port> <target>\"", data)
+		}
+		for i, name := range []string{"priority", "weight", "port"} {
+			if _, err := strconv.ParseUint(fields[i], 10, 16); err != nil {
+				return fmt.Errorf("SRV record %s %q should be in range [0, 65535]", name, fields[i])
+			}
+		}
+		if fields[3] != "." {
+			return validateDnsHostname(fields[3])
+		}
+	case "CAA":
+		parts := strings.SplitN(data, " ", 3)
+		if len(parts) != 3 {
+			return fmt.Errorf("CAA record data %q should be in format '<flags> <tag> \"<value>\"'", data)
+		}
+		if _, err := strconv.ParseUint(parts[0], 10, 8); err != nil {
+			return fmt.Errorf("CAA record flags %q should be in range [0, 255]", parts[0])
+		}
+		if !isDnsTypeInRange(parts[1], DnsCAATags) {
+			return fmt.Errorf("CAA record tag %q is not in range [issue, issuewild, iodef]", parts[1])
+		}
+		if _, err := strconv.Unquote(parts[2]); err != nil {
+			return fmt.Errorf("CAA record value %s should be a quoted string", parts[2])
+		}
+	case "TXT":
+		return validateDnsTxtData(data)
+	}
+	return nil
+}
+
+// validateDnsRecordName checks the record name relative to the zone: "@" for
+// the zone apex, a host name or a wildcard name like *.example.
+func validateDnsRecordName(name string) error {
+	if name == "@" || name == "*" {
+		return nil
+	}
+	if err := validateDnsHostname(strings.TrimPrefix(name, "*.")); err != nil {
+		return fmt.Errorf("%q is not a valid DNS record name, it should be @, a host name or a wildcard name", name)
+	}
+	return nil
+}
+
+// validateDnsHostname checks the host name syntax, the name could be fully qualified.
+func validateDnsHostname(name string) error {
+	hostname := strings.TrimSuffix(name, ".")
+	if hostname == "" || len(hostname) > 253 {
+		return fmt.Errorf("%q is not a valid host name, it should be from 1 to 253 characters long", name)
+	}
+	for _, label := range strings.Split(hostname, ".") {
+		if !dnsLabelRegexp.MatchString(label) {
+			return fmt.Errorf("%q is not a valid host name, label %q should be from 1 to 63 letters, digits, hyphens or underscores and should not start or end with a hyphen", name, label)
+		}
+	}
+	return nil
+}
+
+// validateDnsTxtData checks the TXT record data, which is either a single string
+// or a sequence of quoted strings like "part one" "part two". Every string is
+// limited to 255 bytes, longer values should be split into several strings.
+func validateDnsTxtData(data string) error {
+	if !strings.HasPrefix(data, `"`) {
+		if len(data) > dnsTxtStringMaxLength {
+			return fmt.Errorf("TXT record data is %d bytes long, strings longer than %d bytes should be split into several quoted strings, e.g. \"first part\" \"second part\"", len(data), dnsTxtStringMaxLength)
+		}
+		return nil
+	}
+
+	rest := data
+	for n := 1; rest != ""; n++ {
+		if rest[0] != '"' {
+			return fmt.Errorf("TXT record data %q should be a sequence of quoted strings", data)
+		}
+		size, end := 0, 1
+		for ; end < len(rest) && rest[end] != '"'; end++ {
+			if rest[end] == '\\' {
+				end++
+			}
+			size++
+		}
+		if end >= len(rest) {
+			return fmt.Errorf("TXT record data %q has an unterminated quoted string", data)
+		}
+		if size > dnsTxtStringMaxLength {
+			return fmt.Errorf("TXT record string %d is %d bytes long, the limit is %d bytes", n, size, dnsTxtStringMaxLength)
+		}
+		rest = strings.TrimLeft(rest[end+1:], " ")
+	}
+	return nil
+}
+
+// isDnsZoneApex reports whether the record name points to the zone apex.
+func isDnsZoneApex(name, zoneName string) bool {
+	return name == "@" || (zoneName != "" && sameDnsName(name, zoneName))
+}
+
+// sameDnsName reports whether both names are equal, DNS names are case insensitive.
+func sameDnsName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// dnsCnameConflict returns an error if the record can not coexist with a record
+// of otherType at the same name: a CNAME record should be the only record of the name.
+func dnsCnameConflict(name, recordType, otherType string) error {
+	if recordType != "CNAME" && otherType != "CNAME" {
+		return nil
+	}
+	return fmt.Errorf("CNAME record %s can not coexist with other records of the same name, found %s record", name, otherType)
+}
+
+// addDnsRecordError adds the DNS record validation error at the record attribute path.
+func addDnsRecordError(diags *diag.Diagnostics, recordPath path.Path, record dnsRecordsItemModel, recordErr dnsRecordError) {
+	summary := "Invalid DNS record"
+	detail := fmt.Sprintf("DNS record %s %s is not valid, error: %s", record.Type.ValueString(), record.Name.ValueString(), recordErr.Err.Error())
+	switch {
+	case recordErr.Attribute != "":
+		diags.AddAttributeError(recordPath.AtName(recordErr.Attribute), summary, detail)
+	case len(recordPath.Steps()) != 0:
+		diags.AddAttributeError(recordPath, summary, detail)
+	default:
+		diags.AddError(summary, detail)
+	}
+}
+
+// validateDnsRecordSet checks the DNS records and the conflicts between them.
+// Record paths are the paths of the records in the configuration.
+func validateDnsRecordSet(diags *diag.Diagnostics, records []dnsRecordsItemModel, recordPaths []path.Path, zoneName types.String) {
+	for i, record := range records {
+		for _, recordErr := range validateDnsRecord(record, zoneName.ValueString()) {
+			addDnsRecordError(diags, recordPaths[i], record, recordErr)
+		}
+
+		if record.Name.IsUnknown() || record.Type.ValueString() != "CNAME" {
+			continue
+		}
+		for j, other := range records {
+			if j == i || other.Name.IsUnknown() || other.Type.IsUnknown() || !sameDnsName(record.Name.ValueString(), other.Name.ValueString()) {
+				continue
+			}
+			err := dnsCnameConflict(record.Name.ValueString(), record.Type.ValueString(), other.Type.ValueString())
+			addDnsRecordError(diags, recordPaths[i], record, dnsRecordError{Attribute: "name", Err: err})
+		}
+	}
+}
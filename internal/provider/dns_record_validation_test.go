@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateDnsRecordData(t *testing.T) {
+	testCases := []struct {
+		recordType string
+		data       string
+		valid      bool
+	}{
+		{"A", "192.0.2.10", true},
+		{"A", "2001:db8::10", false},
+		{"A", "192.0.2.300", false},
+		{"AAAA", "2001:db8::10", true},
+		{"AAAA", "192.0.2.10", false},
+		{"AAAA", "::ffff:192.0.2.10", false},
+		{"CNAME", "www.example.com.", true},
+		{"CNAME", "www..example.com.", false},
+		{"CNAME", "-www.example.com", false},
+		{"NS", "ns1.example.com.", true},
+		{"MX", "10 mail.example.com.", true},
+		{"MX", "0 .", true},
+		{"MX", "mail.example.com.", false},
+		{"MX", "70000 mail.example.com.", false},
+		{"SRV", "10 5 5060 sip.example.com.", true},
+		{"SRV", "10 5 sip.example.com.", false},
+		{"CAA", `0 issue "letsencrypt.org"`, true},
+		{"CAA", `0 contactemail "admin@example.com"`, false},
+		{"CAA", `0 issue letsencrypt.org`, false},
+		{"TXT", "v=spf1 -all", true},
+		{"TXT", strings.Repeat("a", 256), false},
+		{"TXT", `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("b", 10) + `"`, true},
+		{"TXT", `"` + strings.Repeat("a", 256) + `"`, false},
+		{"TXT", `"unterminated`, false},
+	}
+
+	for _, testCase := range testCases {
+		err := validateDnsRecordData(testCase.recordType, testCase.data)
+		if testCase.valid && err != nil {
+			t.Errorf("%s %q: unexpected error: %s", testCase.recordType, testCase.data, err)
+		}
+		if !testCase.valid && err == nil {
+			t.Errorf("%s %q: expected an error", testCase.recordType, testCase.data)
+		}
+	}
+}
+
+func TestValidateDnsRecordSet(t *testing.T) {
+	records := []dnsRecordsItemModel{
+		{Name: types.StringValue("@"), Type: types.StringValue("CNAME"), Data: types.StringValue("example.com.")},
+		{Name: types.StringValue("www"), Type: types.StringValue("CNAME"), Data: types.StringValue("example.com.")},
+		{Name: types.StringValue("WWW"), Type: types.StringValue("TXT"), Data: types.StringValue("text")},
+		{Name: types.StringValue("*.static"), Type: types.StringValue("A"), Data: types.StringValue("192.0.2.10")},
+		{Name: types.StringValue("api"), Type: types.StringValue("A"), Data: types.StringUnknown()},
+	}
+	recordPaths := []path.Path{}
+	for i := range records {
+		recordPaths = append(recordPaths, path.Root("dns_records").AtListIndex(i))
+	}
+
+	var diags diag.Diagnostics
+	validateDnsRecordSet(&diags, records, recordPaths, types.StringValue("example.com"))
+
+	expectedPaths := []path.Path{
+		path.Root("dns_records").AtListIndex(0).AtName("name"),
+		path.Root("dns_records").AtListIndex(1).AtName("name"),
+	}
+	if diags.ErrorsCount() != len(expectedPaths) {
+		t.Fatalf("expected %d errors, got: %v", len(expectedPaths), diags)
+	}
+	for i, d := range diags.Errors() {
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		if !ok || !withPath.Path().Equal(expectedPaths[i]) {
+			t.Errorf("error %d: expected path %s, got: %v", i, expectedPaths[i], d)
+		}
+	}
+}
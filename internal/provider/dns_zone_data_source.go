@@ -2,13 +2,18 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 
 	"ngenix/restapi"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -23,23 +28,40 @@ func DnsZoneDataSource() datasource.DataSource {
 }
 
 type dnsZoneDataSource struct {
-	client *restapi.Client
+	client *ngenixClient
 }
 
 // Data model.
 // dnsZoneDataSourceModel maps the data source schema data.
 type dnsZoneDataSourceModel struct {
-	DnsZones []dnsZoneModel `tfsdk:"dns_zones"`
+	NameRegex        types.String   `tfsdk:"name_regex"`
+	RecordType       types.String   `tfsdk:"record_type"`
+	ConfigRefId      types.Int64    `tfsdk:"config_ref_id"`
+	TargetGroupRefId types.Int64    `tfsdk:"targetgroup_ref_id"`
+	DnsZones         []dnsZoneModel `tfsdk:"dns_zones"`
 }
 
 // dnsZoneModel maps schema data.
 type dnsZoneModel struct {
-	Id      types.Int64       `tfsdk:"id"`
-	Name    types.String      `tfsdk:"name"`
-	Records []dnsRecordsModel `tfsdk:"dns_records"`
-	Comment types.String      `tfsdk:"comment"`
+	Id            types.Int64       `tfsdk:"id"`
+	Name          types.String      `tfsdk:"name"`
+	Records       []dnsRecordsModel `tfsdk:"dns_records"`
+	RecordsByName types.Map         `tfsdk:"records_by_name"`
+	Comment       types.String      `tfsdk:"comment"`
 }
 
+var (
+	// dnsRecordsByNameAttrTypes are the attribute types of the records of the records_by_name map.
+	dnsRecordsByNameAttrTypes = map[string]attr.Type{
+		"type":               types.StringType,
+		"data":               types.StringType,
+		"config_ref_id":      types.Int64Type,
+		"targetgroup_ref_id": types.Int64Type,
+	}
+	// dnsRecordsByNameType is the element type of the records_by_name map.
+	dnsRecordsByNameType = types.ListType{ElemType: types.ObjectType{AttrTypes: dnsRecordsByNameAttrTypes}}
+)
+
 type configRefModel struct {
 	ID types.Int64 `tfsdk:"id"`
 }
@@ -64,11 +86,11 @@ func (d *dnsZoneDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*restapi.Client)
+	client, ok := req.ProviderData.(*ngenixClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *restapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -83,7 +105,28 @@ func (d *dnsZoneDataSource) Metadata(_ context.Context, req datasource.MetadataR
 
 func (d *dnsZoneDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Lists DNS zones. The record filters return only the matching records, " +
+			"zones without matching records are skipped.",
 		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only the DNS zones with the name matching this regular expression (RE2 syntax) are returned.",
+			},
+			"record_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only the DNS records of this type are returned.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(DnsRecordTypes...),
+				},
+			},
+			"config_ref_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only the DNS records referencing the config with this ID are returned.",
+			},
+			"targetgroup_ref_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only the DNS records referencing the target group with this ID are returned.",
+			},
 			"dns_zones": schema.ListNestedAttribute{
 				Description: "List of DNS zones records.",
 				Computed:    true,
@@ -97,40 +140,8 @@ func (d *dnsZoneDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 							Computed:    true,
 							Description: "DNS zone name.",
 						},
-						"dns_records": schema.ListNestedAttribute{
-							Description: "List of DNS zone record sets.",
-							Computed:    true,
-							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"name": schema.StringAttribute{
-										Required:    true,
-										Description: "DNS recordset name.",
-									},
-									"type": schema.StringAttribute{
-										Required:    true,
-										Description: "DNS recordset type.",
-									},
-									"data": schema.StringAttribute{
-										Required:    true,
-										Description: "DNS recordset data.",
-									},
-									"config_ref": schema.ObjectAttribute{
-										AttributeTypes: map[string]attr.Type{
-											"id": types.Int64Type,
-										},
-										Optional:    true,
-										Description: "DNS recordset config reference id.",
-									},
-									"targetgroup_ref": schema.ObjectAttribute{
-										AttributeTypes: map[string]attr.Type{
-											"id": types.Int64Type,
-										},
-										Optional:    true,
-										Description: "DNS recordset targetgroup reference id.",
-									},
-								},
-							},
-						},
+						"dns_records":     dnsZoneRecordsSchema(),
+						"records_by_name": dnsZoneRecordsByNameSchema(),
 						"comment": schema.StringAttribute{
 							Computed:    true,
 							Description: "A comment string.",
@@ -142,47 +153,102 @@ func (d *dnsZoneDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 	}
 }
 
+// dnsZoneRecordsSchema returns the schema of the DNS zone data source records.
+func dnsZoneRecordsSchema() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "List of DNS zone record sets.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Required:    true,
+					Description: "DNS recordset name.",
+				},
+				"type": schema.StringAttribute{
+					Required:    true,
+					Description: "DNS recordset type.",
+				},
+				"data": schema.StringAttribute{
+					Required:    true,
+					Description: "DNS recordset data.",
+				},
+				"config_ref": schema.ObjectAttribute{
+					AttributeTypes: map[string]attr.Type{
+						"id": types.Int64Type,
+					},
+					Optional:    true,
+					Description: "DNS recordset config reference id.",
+				},
+				"targetgroup_ref": schema.ObjectAttribute{
+					AttributeTypes: map[string]attr.Type{
+						"id": types.Int64Type,
+					},
+					Optional:    true,
+					Description: "DNS recordset targetgroup reference id.",
+				},
+			},
+		},
+	}
+}
+
+// dnsZoneRecordsByNameSchema returns the schema of the records_by_name map.
+func dnsZoneRecordsByNameSchema() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: dnsRecordsByNameType,
+		Computed:    true,
+		Description: "DNS zone record sets by their names, e.g. records_by_name[\"www\"][0].data. " +
+			"Each record has type, data, config_ref_id and targetgroup_ref_id attributes.",
+	}
+}
+
 func (d *dnsZoneDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state dnsZoneDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		if nameRegex, err = regexp.Compile(state.NameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid DNS zone name regex",
+				fmt.Sprintf("Could not compile name_regex, error: %s", err.Error()),
+			)
+			return
+		}
+	}
+	filter := dnsRecordFilter{
+		recordType:       state.RecordType.ValueString(),
+		configRefId:      state.ConfigRefId.ValueInt64Pointer(),
+		targetGroupRefId: state.TargetGroupRefId.ValueInt64Pointer(),
+	}
 
 	// Getting all DNS zones for provided username.
-	dnsZonesList := d.client.GetAllDnsZonesList()
+	dnsZonesList, err := d.client.GetAllDnsZonesList(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Ngenix DNS zones",
+			fmt.Sprintf("Could not read Ngenix DNS zones, error: %s", err.Error()),
+		)
+		return
+	}
 	// Map response body to model.
+	state.DnsZones = []dnsZoneModel{}
 	for _, dnszone := range dnsZonesList {
-		dnsZoneState := dnsZoneModel{
-			Id:   types.Int64Value(int64(dnszone.ID)),
-			Name: types.StringValue(dnszone.Name),
+		if nameRegex != nil && !nameRegex.MatchString(dnszone.Name) {
+			continue
 		}
-		// DNS record set.
-		for _, record := range dnszone.Records {
-			var recordConfigRef *configRefModel = nil
-			var recordTargetGroupRef *targetGroupRefModel = nil
-			if record.ConfigRef != nil && record.TargetGroupRef != nil {
-				resp.Diagnostics.AddError(
-					"Bad input data format",
-					"misunderstanding with data, config_ref and targetgroup_ref fields, you could use ONLY ONE field at a moment",
-				)
-				return
-			}
-			if record.ConfigRef != nil {
-				recordConfigRef = &configRefModel{
-					ID: types.Int64Value(record.ConfigRef.ID),
-				}
-			}
-			if record.TargetGroupRef != nil {
-				recordTargetGroupRef = &targetGroupRefModel{
-					ID: types.Int64Value(record.TargetGroupRef.ID),
-				}
-			}
-			dnsZoneState.Records = append(dnsZoneState.Records, dnsRecordsModel{
-				Name:           types.StringValue(record.Name),
-				Type:           types.StringValue(record.Type),
-				Data:           types.StringValue(record.Data),
-				ConfigRef:      recordConfigRef,
-				TargetGroupRef: recordTargetGroupRef,
-			})
+		dnsZoneState, err := newDnsZoneModel(dnszone, filter)
+		if err != nil {
+			resp.Diagnostics.AddError("Bad input data format", err.Error())
+			return
+		}
+		// Zones without the records matching the record filters are skipped.
+		if !filter.empty() && len(dnsZoneState.Records) == 0 {
+			continue
 		}
-		dnsZoneState.Comment = types.StringValue(dnszone.Comment)
 
 		state.DnsZones = append(state.DnsZones, dnsZoneState)
 	}
@@ -194,3 +260,79 @@ func (d *dnsZoneDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 }
+
+// dnsRecordFilter holds the record filters of the DNS zone data sources, unset filters match any record.
+type dnsRecordFilter struct {
+	recordType       string
+	configRefId      *int64
+	targetGroupRefId *int64
+}
+
+// empty reports whether no record filter is set.
+func (f dnsRecordFilter) empty() bool {
+	return f.recordType == "" && f.configRefId == nil && f.targetGroupRefId == nil
+}
+
+// match reports whether the record matches all the filters.
+func (f dnsRecordFilter) match(record restapi.Records) bool {
+	return (f.recordType == "" || record.Type == f.recordType) &&
+		(f.configRefId == nil || (record.ConfigRef != nil && record.ConfigRef.ID == *f.configRefId)) &&
+		(f.targetGroupRefId == nil || (record.TargetGroupRef != nil && record.TargetGroupRef.ID == *f.targetGroupRefId))
+}
+
+// newDnsZoneModel maps the DNS zone returned by Ngenix with the records matching the filter to the data source model.
+func newDnsZoneModel(dnszone restapi.DnsZone, filter dnsRecordFilter) (dnsZoneModel, error) {
+	dnsZoneState := dnsZoneModel{
+		Id:      types.Int64Value(int64(dnszone.ID)),
+		Name:    types.StringValue(dnszone.Name),
+		Comment: types.StringValue(dnszone.Comment),
+	}
+	recordsByName := map[string][]attr.Value{}
+	// DNS record set.
+	for _, record := range dnszone.Records {
+		if !filter.match(record) {
+			continue
+		}
+		var recordConfigRef *configRefModel = nil
+		var recordTargetGroupRef *targetGroupRefModel = nil
+		if record.ConfigRef != nil && record.TargetGroupRef != nil {
+			return dnsZoneModel{}, errors.New("misunderstanding with data, config_ref and targetgroup_ref fields, you could use ONLY ONE field at a moment")
+		}
+		configRefId, targetGroupRefId := types.Int64Null(), types.Int64Null()
+		if record.ConfigRef != nil {
+			recordConfigRef = &configRefModel{
+				ID: types.Int64Value(record.ConfigRef.ID),
+			}
+			configRefId = recordConfigRef.ID
+		}
+		if record.TargetGroupRef != nil {
+			recordTargetGroupRef = &targetGroupRefModel{
+				ID: types.Int64Value(record.TargetGroupRef.ID),
+			}
+			targetGroupRefId = recordTargetGroupRef.ID
+		}
+		dnsZoneState.Records = append(dnsZoneState.Records, dnsRecordsModel{
+			Name:           types.StringValue(record.Name),
+			Type:           types.StringValue(record.Type),
+			Data:           types.StringValue(record.Data),
+			ConfigRef:      recordConfigRef,
+			TargetGroupRef: recordTargetGroupRef,
+		})
+		recordsByName[record.Name] = append(recordsByName[record.Name], types.ObjectValueMust(
+			dnsRecordsByNameAttrTypes,
+			map[string]attr.Value{
+				"type":               types.StringValue(record.Type),
+				"data":               types.StringValue(record.Data),
+				"config_ref_id":      configRefId,
+				"targetgroup_ref_id": targetGroupRefId,
+			},
+		))
+	}
+
+	byName := map[string]attr.Value{}
+	for name, records := range recordsByName {
+		byName[name] = types.ListValueMust(dnsRecordsByNameType.ElemType, records)
+	}
+	dnsZoneState.RecordsByName = types.MapValueMust(dnsRecordsByNameType, byName)
+	return dnsZoneState, nil
+}
@@ -1,12 +1,17 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 func TestDnsZoneDataSource(t *testing.T) {
+	testAccFakeAPIOnly(t)
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
@@ -14,10 +19,153 @@ func TestDnsZoneDataSource(t *testing.T) {
 			{
 				Config: providerConfig + `data "ngenix_dns_zones" "test" {}`,
 				Check: resource.ComposeAggregateTestCheckFunc(
-					// Verify number of dns zones returned.
-					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.#", "17"),
+					// Verify number of dns zones returned, zones of other customers are not visible.
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.#", "2"),
+					// Verify the seeded zones.
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.0.name", "seed-one.example"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.0.dns_records.0.data", "192.0.2.10"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.1.name", "seed-two.example"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.1.dns_records.0.config_ref.id", "88903"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.0.records_by_name.www.0.data", "192.0.2.10"),
+				),
+			},
+			// Zones are filtered by name.
+			{
+				Config: providerConfig + `data "ngenix_dns_zones" "test" {
+  name_regex = "^seed-two\\."
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.#", "1"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.0.id", "2"),
+				),
+			},
+			// Zones without the records matching the record filters are skipped.
+			{
+				Config: providerConfig + `data "ngenix_dns_zones" "test" {
+  record_type   = "A"
+  config_ref_id = 88903
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.#", "1"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.0.name", "seed-two.example"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.0.records_by_name.cdn.0.config_ref_id", "88903"),
+				),
+			},
+			{
+				Config: providerConfig + `data "ngenix_dns_zones" "test" {
+  targetgroup_ref_id = 1
+}`,
+				Check: resource.TestCheckResourceAttr("data.ngenix_dns_zones.test", "dns_zones.#", "0"),
+			},
+		},
+	})
+}
+
+func TestDnsZoneSingleDataSource(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Zones are looked up by name.
+			{
+				Config: providerConfig + `data "ngenix_dns_zone" "test" {
+  name = "seed-one.example"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone.test", "id", "1"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone.test", "dns_records.#", "1"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone.test", "records_by_name.www.0.type", "A"),
+				),
+			},
+			// Zones are looked up by ID.
+			{
+				Config: providerConfig + `data "ngenix_dns_zone" "test" {
+  id = 2
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone.test", "name", "seed-two.example"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone.test", "comment", "Seeded"),
 				),
 			},
+			// Zones of other customers are not found.
+			{
+				Config: providerConfig + `data "ngenix_dns_zone" "test" {
+  id = 3
+}`,
+				ExpectError: regexp.MustCompile("DNS zone not found"),
+			},
 		},
 	})
 }
+
+func TestFindDnsZoneByName(t *testing.T) {
+	dnsZones := []restapi.DnsZone{
+		{ID: 1, Name: "example.com"},
+		{ID: 2, Name: "example.org"},
+		{ID: 3, Name: "example.org"},
+	}
+
+	found, err := findDnsZoneByName(dnsZones, "example.com")
+	if err != nil || found.ID != 1 {
+		t.Fatalf("expected DNS zone 1, got: %v, %v", found, err)
+	}
+	if _, err := findDnsZoneByName(dnsZones, "example.org"); err == nil {
+		t.Error("expected an error for several DNS zones with the name")
+	}
+	if _, err := findDnsZoneByName(dnsZones, "example.net"); err == nil {
+		t.Error("expected an error for a missing DNS zone")
+	}
+}
+
+func TestNewDnsZoneModel(t *testing.T) {
+	dnszone := restapi.DnsZone{
+		ID:   1,
+		Name: "example.com",
+		Records: []restapi.Records{
+			{Name: "www", Type: "A", Data: "192.0.2.1"},
+			{Name: "www", Type: "AAAA", Data: "2001:db8::1"},
+			{Name: "cdn", Type: "A", ConfigRef: &restapi.ConfigRef{ID: 10}},
+			{Name: "app", Type: "A", TargetGroupRef: &restapi.TargetGroupRef{ID: 20}},
+		},
+	}
+	configRefId, targetGroupRefId := int64(10), int64(20)
+
+	tests := map[string]struct {
+		filter  dnsRecordFilter
+		records int
+		names   int
+	}{
+		"no filters":      {filter: dnsRecordFilter{}, records: 4, names: 3},
+		"record type":     {filter: dnsRecordFilter{recordType: "A"}, records: 3, names: 3},
+		"config ref":      {filter: dnsRecordFilter{configRefId: &configRefId}, records: 1, names: 1},
+		"target group":    {filter: dnsRecordFilter{targetGroupRefId: &targetGroupRefId}, records: 1, names: 1},
+		"no matches":      {filter: dnsRecordFilter{recordType: "MX"}, records: 0, names: 0},
+		"several filters": {filter: dnsRecordFilter{recordType: "AAAA", configRefId: &configRefId}, records: 0, names: 0},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			model, err := newDnsZoneModel(dnszone, test.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(model.Records) != test.records {
+				t.Errorf("expected %d records, got %d", test.records, len(model.Records))
+			}
+			if len(model.RecordsByName.Elements()) != test.names {
+				t.Errorf("expected %d record names, got %d", test.names, len(model.RecordsByName.Elements()))
+			}
+		})
+	}
+
+	model, _ := newDnsZoneModel(dnszone, dnsRecordFilter{})
+	if www, ok := model.RecordsByName.Elements()["www"].(types.List); !ok || len(www.Elements()) != 2 {
+		t.Errorf("expected 2 www records, got %s", model.RecordsByName)
+	}
+
+	dnszone.Records = append(dnszone.Records, restapi.Records{
+		Name: "bad", Type: "A", ConfigRef: &restapi.ConfigRef{ID: 1}, TargetGroupRef: &restapi.TargetGroupRef{ID: 2},
+	})
+	if _, err := newDnsZoneModel(dnszone, dnsRecordFilter{}); err == nil {
+		t.Error("expected an error for a record with both config_ref and targetgroup_ref")
+	}
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource = &dnsZoneFileDataSource{}
+)
+
+// DnsZoneFileDataSource is a helper function to simplify the provider implementation.
+func DnsZoneFileDataSource() datasource.DataSource {
+	return &dnsZoneFileDataSource{}
+}
+
+// dnsZoneFileDataSource parses a zone file, it does not call the Ngenix API.
+type dnsZoneFileDataSource struct{}
+
+// dnsZoneFileDataSourceModel maps the data source schema data.
+type dnsZoneFileDataSourceModel struct {
+	Content types.String          `tfsdk:"content"`
+	Origin  types.String          `tfsdk:"origin"`
+	Records []dnsRecordsItemModel `tfsdk:"dns_records"`
+}
+
+func (d *dnsZoneFileDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_zone_file"
+}
+
+func (d *dnsZoneFileDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Parses a DNS zone master file (RFC 1035), e.g. exported from BIND, to DNS records " +
+			"which could be assigned to the dns_records attribute of the ngenix_dnszone resource.",
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Required:    true,
+				Description: "Zone file content.",
+			},
+			"origin": schema.StringAttribute{
+				Optional:    true,
+				Description: "Zone origin used to resolve relative names, e.g. example.com. The first $ORIGIN directive of the zone file is used if not set.",
+			},
+			"dns_records": schema.ListNestedAttribute{
+				Computed: true,
+				Description: "DNS records of the zone file. SOA records and NS records of the zone apex are skipped, " +
+					"since they are managed by Ngenix. TTL values are skipped, Ngenix DNS records have no TTL.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "DNS record name relative to the zone origin.",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "DNS record type.",
+						},
+						"data": schema.StringAttribute{
+							Computed:    true,
+							Description: "DNS record data.",
+						},
+						"priority": schema.Int64Attribute{
+							Computed:    true,
+							Description: "MX record preference or SRV record priority.",
+						},
+						"weight": schema.Int64Attribute{
+							Computed:    true,
+							Description: "SRV record weight.",
+						},
+						"port": schema.Int64Attribute{
+							Computed:    true,
+							Description: "SRV record port.",
+						},
+						"flags": schema.Int64Attribute{
+							Computed:    true,
+							Description: "CAA record flags.",
+						},
+						"tag": schema.StringAttribute{
+							Computed:    true,
+							Description: "CAA record property tag.",
+						},
+						// References could not be set in a zone file, they are kept
+						// for the records to match the ngenix_dnszone records type.
+						"config_ref": schema.ObjectAttribute{
+							AttributeTypes: map[string]attr.Type{
+								"id": types.Int64Type,
+							},
+							Computed:    true,
+							Description: "Always null, zone files could not reference configs.",
+						},
+						"targetgroup_ref": schema.ObjectAttribute{
+							AttributeTypes: map[string]attr.Type{
+								"id": types.Int64Type,
+							},
+							Computed:    true,
+							Description: "Always null, zone files could not reference target groups.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *dnsZoneFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state dnsZoneFileDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	records, err := parseZoneFile(state.Content.ValueString(), state.Origin.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Error parsing zone file",
+			fmt.Sprintf("Could not parse zone file, error: %s", err.Error()),
+		)
+		return
+	}
+	state.Records, err = DNSRecordsItemModelTransformation(records)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS zone Records creation and validation process",
+			fmt.Sprintf("Could not create DNS zone Records, error: %s", err.Error()),
+		)
+		return
+	}
+
+	// Set state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
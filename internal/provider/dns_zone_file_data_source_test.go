@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDnsZoneFileDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing, the parsed records are used by the DNS zone resource.
+			{
+				Config: providerConfig + `
+data "ngenix_dns_zone_file" "test" {
+  content = <<EOT
+$ORIGIN ngenixterraformzonefile.ru.
+$TTL 3600
+@ IN SOA ns1.ngenix.net. hostmaster.ngenixterraformzonefile.ru. ( 1 7200 3600 1209600 3600 )
+@ IN NS ns1.ngenix.net.
+www 300 IN A 23.12.76.128
+@ IN MX 10 mail
+@ IN TXT "v=spf1 -all"
+EOT
+}
+
+resource "ngenix_dnszone" "test" {
+  name        = "ngenixterraformzonefile.ru"
+  dns_records = data.ngenix_dns_zone_file.test.dns_records
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify the parsed records, SOA and NS records of the apex are skipped.
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone_file.test", "dns_records.#", "3"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone_file.test", "dns_records.0.name", "www"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone_file.test", "dns_records.1.type", "MX"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone_file.test", "dns_records.1.priority", "10"),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone_file.test", "dns_records.1.data", "mail.ngenixterraformzonefile.ru."),
+					resource.TestCheckResourceAttr("data.ngenix_dns_zone_file.test", "dns_records.2.data", "v=spf1 -all"),
+					// Verify the DNS zone is created from the zone file records.
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "3"),
+					resource.TestMatchResourceAttr("ngenix_dnszone.test", "zone_file", regexp.MustCompile(`^\$ORIGIN ngenixterraformzonefile\.ru\.\n`)),
+					resource.TestMatchResourceAttr("ngenix_dnszone.test", "zone_file", regexp.MustCompile(`(?m)^www\tIN\tA\t23\.12\.76\.128$`)),
+					resource.TestMatchResourceAttr("ngenix_dnszone.test", "zone_file", regexp.MustCompile(`(?m)^@\tIN\tMX\t10 mail\.ngenixterraformzonefile\.ru\.$`)),
+				),
+			},
+		},
+	})
+}
@@ -5,27 +5,36 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"ngenix/restapi"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &dnsZoneResource{}
-	_ resource.ResourceWithConfigure   = &dnsZoneResource{}
-	_ resource.ResourceWithImportState = &dnsZoneResource{}
+	_ resource.Resource                   = &dnsZoneResource{}
+	_ resource.ResourceWithConfigure      = &dnsZoneResource{}
+	_ resource.ResourceWithImportState    = &dnsZoneResource{}
+	_ resource.ResourceWithUpgradeState   = &dnsZoneResource{}
+	_ resource.ResourceWithValidateConfig = &dnsZoneResource{}
 )
 
 // NewDnsZoneResource is a helper function to simplify the provider implementation.
@@ -35,17 +44,20 @@ func DnsZoneResource() resource.Resource {
 
 // dnsZoneResource is the resource implementation.
 type dnsZoneResource struct {
-	client *restapi.Client
+	client *ngenixClient
 }
 
 // Data model
 // dnsZoneDataSourceModel maps the data source schema data.
 type dnsZoneResourceModel struct {
-	ID          types.String          `tfsdk:"id"`
-	Name        types.String          `tfsdk:"name"`
-	Records     []dnsRecordsItemModel `tfsdk:"dns_records"`
-	Comment     types.String          `tfsdk:"comment"`
-	LastUpdated types.String          `tfsdk:"last_updated"`
+	ID                     types.String          `tfsdk:"id"`
+	Name                   types.String          `tfsdk:"name"`
+	Records                []dnsRecordsItemModel `tfsdk:"dns_records"`
+	IgnoreUnmanagedRecords types.Bool            `tfsdk:"ignore_unmanaged_records"`
+	Comment                types.String          `tfsdk:"comment"`
+	ZoneFile               types.String          `tfsdk:"zone_file"`
+	LastUpdated            types.String          `tfsdk:"last_updated"`
+	Timeouts               timeouts.Value        `tfsdk:"timeouts"`
 }
 
 type configRefItemModel struct {
@@ -60,12 +72,20 @@ type dnsRecordsItemModel struct {
 	Name           types.String             `tfsdk:"name"`
 	Type           types.String             `tfsdk:"type"`
 	Data           types.String             `tfsdk:"data"`
+	Priority       types.Int64              `tfsdk:"priority"`
+	Weight         types.Int64              `tfsdk:"weight"`
+	Port           types.Int64              `tfsdk:"port"`
+	Flags          types.Int64              `tfsdk:"flags"`
+	Tag            types.String             `tfsdk:"tag"`
 	ConfigRef      *configRefItemModel      `tfsdk:"config_ref"`
 	TargetGroupRef *targetGroupRefItemModel `tfsdk:"targetgroup_ref"`
 }
 
 var DnsRecordTypes = []string{"A", "CNAME", "MX", "AAAA", "SRV", "NS", "TXT", "CAA"}
 
+// DnsCAATags lists the property tags supported for CAA records.
+var DnsCAATags = []string{"issue", "issuewild", "iodef"}
+
 func isDnsTypeInRange(value string, list []string) bool {
 	for _, v := range list {
 		if v == value {
@@ -75,6 +95,86 @@ func isDnsTypeInRange(value string, list []string) bool {
 	return false
 }
 
+// dnsZoneID converts the DNS zone ID stored in the state to the API identifier.
+func dnsZoneID(id types.String) (int, error) {
+	zoneId, err := strconv.Atoi(id.ValueString())
+	if err != nil || zoneId <= 0 {
+		return 0, fmt.Errorf("DNS zone ID %q is not a valid numeric ID", id.ValueString())
+	}
+	return zoneId, nil
+}
+
+// dnsZoneLocks serializes read-modify-write cycles of DNS zone records, since
+// records of one zone could be managed by several resources applied in parallel.
+var dnsZoneLocks = struct {
+	sync.Mutex
+	zones map[int]*sync.Mutex
+}{zones: map[int]*sync.Mutex{}}
+
+// lockDnsZone locks the DNS zone records and returns the unlock function.
+func lockDnsZone(zoneId int) func() {
+	dnsZoneLocks.Lock()
+	zoneLock, ok := dnsZoneLocks.zones[zoneId]
+	if !ok {
+		zoneLock = &sync.Mutex{}
+		dnsZoneLocks.zones[zoneId] = zoneLock
+	}
+	dnsZoneLocks.Unlock()
+
+	zoneLock.Lock()
+	return zoneLock.Unlock
+}
+
+// sameDnsRecord reports whether both records have equal name, type, data and references.
+func sameDnsRecord(a, b restapi.Records) bool {
+	if a.Name != b.Name || a.Type != b.Type || a.Data != b.Data {
+		return false
+	}
+	if (a.ConfigRef == nil) != (b.ConfigRef == nil) || (a.ConfigRef != nil && a.ConfigRef.ID != b.ConfigRef.ID) {
+		return false
+	}
+	if (a.TargetGroupRef == nil) != (b.TargetGroupRef == nil) || (a.TargetGroupRef != nil && a.TargetGroupRef.ID != b.TargetGroupRef.ID) {
+		return false
+	}
+	return true
+}
+
+// filterDnsRecords returns the records which are present in managed when keep
+// is true, or the records which are absent from managed otherwise.
+func filterDnsRecords(records, managed []restapi.Records, keep bool) []restapi.Records {
+	filtered := []restapi.Records{}
+	for _, record := range records {
+		found := false
+		for _, managedRecord := range managed {
+			if sameDnsRecord(record, managedRecord) {
+				found = true
+				break
+			}
+		}
+		if found == keep {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// dnsRecordsState maps DNS zone records read from the API to the dns_records
+// state value. With ignore_unmanaged_records only the managed records are kept.
+// An empty result stays null if dns_records was not set, to match the configuration.
+func dnsRecordsState(records, managed []restapi.Records, ignoreUnmanaged types.Bool, configured []dnsRecordsItemModel) ([]dnsRecordsItemModel, error) {
+	if ignoreUnmanaged.ValueBool() {
+		records = filterDnsRecords(records, managed, true)
+	}
+	dnsRecordsItems, err := DNSRecordsItemModelTransformation(records)
+	if err != nil {
+		return nil, err
+	}
+	if len(dnsRecordsItems) == 0 && configured == nil {
+		return nil, nil
+	}
+	return preferConfiguredDnsRecords(dnsRecordsItems, configured), nil
+}
+
 // Configure adds the provider configured client to the data source.
 func (d *dnsZoneResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -83,11 +183,11 @@ func (d *dnsZoneResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*restapi.Client)
+	client, ok := req.ProviderData.(*ngenixClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *restapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -101,14 +201,85 @@ func (r *dnsZoneResource) Metadata(_ context.Context, req resource.MetadataReque
 	resp.TypeName = req.ProviderTypeName + "_dnszone"
 }
 
+// dnsRecordsNestedObject describes a DNS record of the dns_records attribute.
+func dnsRecordsNestedObject() schema.NestedAttributeObject {
+	return schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "DNS record name",
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "DNS record type",
+			},
+			"data": schema.StringAttribute{
+				Optional:    true,
+				Description: "DNS record data. For MX, SRV and CAA records with the type specific fields set, the mail exchange host, the target host or the property value respectively.",
+			},
+			"priority": schema.Int64Attribute{
+				Optional:    true,
+				Description: "MX record preference or SRV record priority.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+			},
+			"weight": schema.Int64Attribute{
+				Optional:    true,
+				Description: "SRV record weight.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+			},
+			"port": schema.Int64Attribute{
+				Optional:    true,
+				Description: "SRV record port.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+			},
+			"flags": schema.Int64Attribute{
+				Optional:    true,
+				Description: "CAA record flags.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 255),
+				},
+			},
+			"tag": schema.StringAttribute{
+				Optional:    true,
+				Description: "CAA record property tag: issue, issuewild or iodef.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(DnsCAATags...),
+				},
+			},
+			"config_ref": schema.ObjectAttribute{
+				AttributeTypes: map[string]attr.Type{
+					"id": types.Int64Type,
+				},
+				Optional:    true,
+				Description: "DNS record config reference id",
+			},
+			"targetgroup_ref": schema.ObjectAttribute{
+				AttributeTypes: map[string]attr.Type{
+					"id": types.Int64Type,
+				},
+				Optional:    true,
+				Description: "DNS record target group reference id",
+			},
+		},
+	}
+}
+
 // Schema defines the schema for the resource.
-func (r *dnsZoneResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *dnsZoneResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a DNS zone.",
+		// Version 1 stores dns_records as a set instead of a list.
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
-				Description: "Placeholder identifier attribute.",
+				Description: "DNS zone ID.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -117,43 +288,27 @@ func (r *dnsZoneResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Computed:    true,
 				Description: "Timestamp of the last Terraform update of the DNS zone.",
 			},
+			"zone_file": schema.StringAttribute{
+				Computed:    true,
+				Description: "All records of the DNS zone in the zone file (RFC 1035) format. Records referencing a config or a target group are written as comments.",
+			},
 			"name": schema.StringAttribute{
 				Required:    true,
-				Description: "DNS zone name",
+				Description: "DNS zone name. Changing the name re-creates the DNS zone.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"dns_records": schema.ListNestedAttribute{
+			"dns_records": schema.SetNestedAttribute{
+				Optional:     true,
+				Description:  "DNS zone records. The TTL of the records cannot be set, the Ngenix core library has no TTL for DNS records.",
+				NestedObject: dnsRecordsNestedObject(),
+			},
+			"ignore_unmanaged_records": schema.BoolAttribute{
+				Description: "Ignore DNS records which are not listed in dns_records, e.g. managed by ngenix_dns_record resources. Such records are neither shown in the state nor removed on update.",
 				Optional:    true,
-				Description: "DNS zone records",
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							Required:    true,
-							Description: "DNS record name",
-						},
-						"type": schema.StringAttribute{
-							Required:    true,
-							Description: "DNS record type",
-						},
-						"data": schema.StringAttribute{
-							Optional:    true,
-							Description: "DNS record data",
-						},
-						"config_ref": schema.ObjectAttribute{
-							AttributeTypes: map[string]attr.Type{
-								"id": types.Int64Type,
-							},
-							Optional:    true,
-							Description: "DNS record config reference id",
-						},
-						"targetgroup_ref": schema.ObjectAttribute{
-							AttributeTypes: map[string]attr.Type{
-								"id": types.Int64Type,
-							},
-							Optional:    true,
-							Description: "DNS record target group reference id",
-						},
-					},
-				},
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
 			},
 			"comment": schema.StringAttribute{
 				Description: "DNS zone resource comment",
@@ -165,115 +320,391 @@ func (r *dnsZoneResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
-func (r *dnsZoneResource) DNSRecordsModelTransformation(records []dnsRecordsItemModel) ([]restapi.Records, error) {
+// dnsZoneFieldPath maps the fields of the DNS zone rejected by Ngenix API to the
+// resource attributes. The planned records are sent first, so records[i] is
+// the element of dns_records with the i-th planned record, the unmanaged
+// records sent after them have no attributes.
+func dnsZoneFieldPath(ctx context.Context, recordsSet types.Set, records []dnsRecordsItemModel) func(field apiField) (path.Path, bool) {
+	return func(field apiField) (path.Path, bool) {
+		if field.name == "name" || field.name == "comment" {
+			return path.Root(field.name), field.index < 0 && field.attribute == ""
+		}
+		objectType, ok := recordsSet.ElementType(ctx).(types.ObjectType)
+		if field.name != "records" || !ok || field.index < 0 || field.index >= len(records) {
+			return path.Empty(), false
+		}
+		element, diags := types.ObjectValueFrom(ctx, objectType.AttrTypes, records[field.index])
+		if diags.HasError() {
+			return path.Empty(), false
+		}
+		recordPath := path.Root("dns_records").AtSetValue(element)
+		if field.attribute == "" {
+			return recordPath, true
+		}
+		return recordPath.AtName(attributeName(field.attribute)), true
+	}
+}
+
+// ValidateConfig checks the DNS records at plan time.
+func (r *dnsZoneResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var zoneName types.String
+	var dnsRecords types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &zoneName)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("dns_records"), &dnsRecords)...)
+	if resp.Diagnostics.HasError() || dnsRecords.IsUnknown() || dnsRecords.IsNull() {
+		return
+	}
+
+	records := []dnsRecordsItemModel{}
+	recordPaths := []path.Path{}
+	for _, element := range dnsRecords.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() {
+			continue
+		}
+		var record dnsRecordsItemModel
+		// Records with unknown references are checked once the values are known.
+		if diags := object.As(ctx, &record, basetypes.ObjectAsOptions{}); diags.HasError() {
+			continue
+		}
+		records = append(records, record)
+		recordPaths = append(recordPaths, path.Root("dns_records").AtSetValue(element))
+	}
+	validateDnsRecordSet(&resp.Diagnostics, records, recordPaths, zoneName)
+}
+
+func DNSRecordsModelTransformation(records []dnsRecordsItemModel) ([]restapi.Records, error) {
 	dnsRecordSet := []restapi.Records{}
 	for _, record := range records {
 		// Requirement: Records.Type is in range onf values.
-		if isDnsTypeInRange(record.Type.ValueString(), DnsRecordTypes) {
-			// Requirement: DNS record Type = A.
-			if record.Type.ValueString() == "A" {
-				if record.ConfigRef != nil && len(record.Data.ValueString()) != 0 {
-					return nil, errors.New("both fields <data> and <config_ref> are not supported")
-				} else if record.TargetGroupRef != nil && len(record.Data.ValueString()) != 0 {
-					return nil, errors.New("both fields <data> and <targetgroup_ref> are not supported")
-				} else if record.ConfigRef == nil && record.TargetGroupRef == nil {
-					dnsRecordSet = append(dnsRecordSet, restapi.Records{
-						Name: record.Name.ValueString(),
-						Type: record.Type.ValueString(),
-						Data: record.Data.ValueString(),
-					})
-				} else if record.ConfigRef != nil {
-					recordConfigRef := &restapi.ConfigRef{
-						ID: record.ConfigRef.ID.ValueInt64(),
-					}
-					dnsRecordSet = append(dnsRecordSet, restapi.Records{
-						Name:      record.Name.ValueString(),
-						Type:      record.Type.ValueString(),
-						ConfigRef: recordConfigRef,
-					})
-				} else if record.TargetGroupRef != nil {
-					recordTargetGroupRef := &restapi.TargetGroupRef{
-						ID: record.TargetGroupRef.ID.ValueInt64(),
-					}
-					dnsRecordSet = append(dnsRecordSet, restapi.Records{
-						Name:           record.Name.ValueString(),
-						Type:           record.Type.ValueString(),
-						TargetGroupRef: recordTargetGroupRef,
-					})
-				} else {
-					return nil, errors.New("misunderstanding with data, config_ref and targetgroup_ref fields")
+		if !isDnsTypeInRange(record.Type.ValueString(), DnsRecordTypes) {
+			return nil, errors.New("dns record type value is not in range [A, CNAME, MX, AAAA, SRV, NS, TXT, CAA]")
+		}
+		data, err := dnsRecordData(record)
+		if err != nil {
+			return nil, err
+		}
+		dnsRecord := restapi.Records{
+			Name: record.Name.ValueString(),
+			Type: record.Type.ValueString(),
+			Data: data,
+		}
+		// Requirement: DNS record Type = A.
+		if record.Type.ValueString() == "A" {
+			if record.ConfigRef != nil && len(data) != 0 {
+				return nil, errors.New("both fields <data> and <config_ref> are not supported")
+			} else if record.TargetGroupRef != nil && len(data) != 0 {
+				return nil, errors.New("both fields <data> and <targetgroup_ref> are not supported")
+			} else if record.ConfigRef != nil {
+				dnsRecord.ConfigRef = &restapi.ConfigRef{
+					ID: record.ConfigRef.ID.ValueInt64(),
+				}
+			} else if record.TargetGroupRef != nil {
+				dnsRecord.TargetGroupRef = &restapi.TargetGroupRef{
+					ID: record.TargetGroupRef.ID.ValueInt64(),
 				}
-			} else {
-				dnsRecordSet = append(dnsRecordSet, restapi.Records{
-					Name: record.Name.ValueString(),
-					Type: record.Type.ValueString(),
-					Data: record.Data.ValueString(),
-				})
 			}
-		} else {
-			return nil, errors.New("dns record type value is not in range [A, CNAME, MX, AAAA, SRV, NS, TXT, CAA]")
 		}
+		dnsRecordSet = append(dnsRecordSet, dnsRecord)
 	}
 	return dnsRecordSet, nil
 }
 
-func (r *dnsZoneResource) DNSRecordsItemModelTransformation(records []restapi.Records) ([]dnsRecordsItemModel, error) {
+// dnsRecordData serializes the type specific record fields to the record data
+// in the zone file presentation format, the same format the records with
+// hand-packed data use, e.g. "10 5 2000 test.srv.test." for SRV:
+// "<priority> <host>" for MX, "<priority> <weight> <port> <target>" for SRV and
+// "<flags> <tag> \"<value>\"" for CAA. Without these fields data is sent as is,
+// so records with hand-packed data keep working.
+func dnsRecordData(record dnsRecordsItemModel) (string, error) {
+	recordType := record.Type.ValueString()
+	data := record.Data.ValueString()
+	for _, field := range []struct {
+		name  string
+		value types.Int64
+		max   int64
+	}{
+		{"priority", record.Priority, 65535},
+		{"weight", record.Weight, 65535},
+		{"port", record.Port, 65535},
+		{"flags", record.Flags, 255},
+	} {
+		if !field.value.IsNull() && !field.value.IsUnknown() && (field.value.ValueInt64() < 0 || field.value.ValueInt64() > field.max) {
+			return "", fmt.Errorf("dns record <%s> should be in range [0, %d], got: %d", field.name, field.max, field.value.ValueInt64())
+		}
+	}
+
+	switch recordType {
+	case "MX":
+		if !record.Weight.IsNull() || !record.Port.IsNull() || !record.Flags.IsNull() || !record.Tag.IsNull() {
+			return "", errors.New("only <priority> is supported for MX dns records")
+		}
+		if record.Priority.IsNull() {
+			return data, nil
+		}
+		return fmt.Sprintf("%d %s", record.Priority.ValueInt64(), data), nil
+	case "SRV":
+		if !record.Flags.IsNull() || !record.Tag.IsNull() {
+			return "", errors.New("only <priority>, <weight> and <port> are supported for SRV dns records")
+		}
+		if record.Priority.IsNull() && record.Weight.IsNull() && record.Port.IsNull() {
+			return data, nil
+		}
+		if record.Priority.IsNull() || record.Weight.IsNull() || record.Port.IsNull() {
+			return "", errors.New("fields <priority>, <weight> and <port> should be set together for SRV dns records")
+		}
+		return fmt.Sprintf("%d %d %d %s", record.Priority.ValueInt64(), record.Weight.ValueInt64(), record.Port.ValueInt64(), data), nil
+	case "CAA":
+		if !record.Priority.IsNull() || !record.Weight.IsNull() || !record.Port.IsNull() {
+			return "", errors.New("only <flags> and <tag> are supported for CAA dns records")
+		}
+		if record.Flags.IsNull() && record.Tag.IsNull() {
+			return data, nil
+		}
+		if record.Flags.IsNull() || record.Tag.IsNull() {
+			return "", errors.New("fields <flags> and <tag> should be set together for CAA dns records")
+		}
+		if !record.Tag.IsUnknown() && !isDnsTypeInRange(record.Tag.ValueString(), DnsCAATags) {
+			return "", fmt.Errorf("dns record <tag> value is not in range [issue, issuewild, iodef], got: %s", record.Tag.ValueString())
+		}
+		return fmt.Sprintf("%d %s %s", record.Flags.ValueInt64(), record.Tag.ValueString(), strconv.Quote(data)), nil
+	default:
+		if !record.Priority.IsNull() || !record.Weight.IsNull() || !record.Port.IsNull() || !record.Flags.IsNull() || !record.Tag.IsNull() {
+			return "", fmt.Errorf("fields <priority>, <weight>, <port>, <flags> and <tag> are not supported for %s dns records", recordType)
+		}
+		return data, nil
+	}
+}
+
+// setDnsRecordData parses the record data read from the API to the type
+// specific record fields. Data which does not match the expected format is
+// kept as is.
+func setDnsRecordData(item *dnsRecordsItemModel, data string) {
+	item.Data = types.StringValue(data)
+	fields := strings.Fields(data)
+	switch item.Type.ValueString() {
+	case "MX":
+		if len(fields) != 2 {
+			return
+		}
+		priority, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return
+		}
+		item.Priority = types.Int64Value(int64(priority))
+		item.Data = types.StringValue(fields[1])
+	case "SRV":
+		if len(fields) != 4 {
+			return
+		}
+		priority, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return
+		}
+		weight, err := strconv.ParseUint(fields[1], 10, 16)
+		if err != nil {
+			return
+		}
+		port, err := strconv.ParseUint(fields[2], 10, 16)
+		if err != nil {
+			return
+		}
+		item.Priority = types.Int64Value(int64(priority))
+		item.Weight = types.Int64Value(int64(weight))
+		item.Port = types.Int64Value(int64(port))
+		item.Data = types.StringValue(fields[3])
+	case "CAA":
+		parts := strings.SplitN(data, " ", 3)
+		if len(parts) != 3 || !isDnsTypeInRange(parts[1], DnsCAATags) {
+			return
+		}
+		flags, err := strconv.ParseUint(parts[0], 10, 8)
+		if err != nil {
+			return
+		}
+		value, err := strconv.Unquote(parts[2])
+		if err != nil {
+			return
+		}
+		item.Flags = types.Int64Value(int64(flags))
+		item.Tag = types.StringValue(parts[1])
+		item.Data = types.StringValue(value)
+	}
+}
+
+func DNSRecordsItemModelTransformation(records []restapi.Records) ([]dnsRecordsItemModel, error) {
 	dnsRecordsItems := []dnsRecordsItemModel{}
 	for _, record := range records {
 		// Requirement: Records.Type is in range onf values.
-		if isDnsTypeInRange(record.Type, DnsRecordTypes) {
-			// Requirement: DNS record Type = A.
-			if record.Type == "A" {
-				if record.ConfigRef != nil && len(record.Data) != 0 {
-					return nil, errors.New("both fields <data> and <config_ref> are not supported")
-				} else if record.TargetGroupRef != nil && len(record.Data) != 0 {
-					return nil, errors.New("both fields <data> and <targetgroup_ref> are not supported")
-				} else if record.ConfigRef == nil && record.TargetGroupRef == nil {
-					dnsRecordsItems = append(dnsRecordsItems, dnsRecordsItemModel{
-						Name: types.StringValue(record.Name),
-						Type: types.StringValue(record.Type),
-						Data: types.StringValue(record.Data),
-					})
-				} else if record.ConfigRef != nil {
-					recordConfigRefItem := &configRefItemModel{
-						ID: types.Int64Value(record.ConfigRef.ID),
-					}
-					dnsRecordsItems = append(dnsRecordsItems, dnsRecordsItemModel{
-						Name:      types.StringValue(record.Name),
-						Type:      types.StringValue(record.Type),
-						ConfigRef: recordConfigRefItem,
-					})
-				} else if record.TargetGroupRef != nil {
-					recordTargetGroupRefItem := &targetGroupRefItemModel{
-						ID: types.Int64Value(record.TargetGroupRef.ID),
-					}
-					dnsRecordsItems = append(dnsRecordsItems, dnsRecordsItemModel{
-						Name:           types.StringValue(record.Name),
-						Type:           types.StringValue(record.Type),
-						TargetGroupRef: recordTargetGroupRefItem,
-					})
-				} else {
-					return nil, errors.New("misunderstanding with data, config_ref and targetgroup_ref fields, you could use ONLY ONE field at a moment")
+		if !isDnsTypeInRange(record.Type, DnsRecordTypes) {
+			return nil, errors.New("dns record type should be in range [A, CNAME, MX, AAAA, SRV, NS, TXT, CAA]")
+		}
+		dnsRecordsItem := dnsRecordsItemModel{
+			Name: types.StringValue(record.Name),
+			Type: types.StringValue(record.Type),
+		}
+		// Requirement: DNS record Type = A.
+		if record.Type == "A" {
+			if record.ConfigRef != nil && len(record.Data) != 0 {
+				return nil, errors.New("both fields <data> and <config_ref> are not supported")
+			} else if record.TargetGroupRef != nil && len(record.Data) != 0 {
+				return nil, errors.New("both fields <data> and <targetgroup_ref> are not supported")
+			} else if record.ConfigRef != nil {
+				dnsRecordsItem.ConfigRef = &configRefItemModel{
+					ID: types.Int64Value(record.ConfigRef.ID),
+				}
+			} else if record.TargetGroupRef != nil {
+				dnsRecordsItem.TargetGroupRef = &targetGroupRefItemModel{
+					ID: types.Int64Value(record.TargetGroupRef.ID),
 				}
 			} else {
-				dnsRecordsItems = append(dnsRecordsItems, dnsRecordsItemModel{
-					Name: types.StringValue(record.Name),
-					Type: types.StringValue(record.Type),
-					Data: types.StringValue(record.Data),
-				})
+				dnsRecordsItem.Data = types.StringValue(record.Data)
 			}
 		} else {
-			return nil, errors.New("dns record type should be in range [A, CNAME, MX, AAAA, SRV, NS, TXT, CAA]")
+			setDnsRecordData(&dnsRecordsItem, record.Data)
 		}
+		dnsRecordsItems = append(dnsRecordsItems, dnsRecordsItem)
 	}
 	return dnsRecordsItems, nil
 }
 
+// preferConfiguredDnsRecords replaces the records read from the API with the
+// configured records they are equal to once serialized, so that hand-packed
+// data, e.g. "10 mail.example.com." for MX, does not produce a diff.
+func preferConfiguredDnsRecords(items, configured []dnsRecordsItemModel) []dnsRecordsItemModel {
+	for i := range items {
+		record, err := DNSRecordsModelTransformation(items[i : i+1])
+		if err != nil {
+			continue
+		}
+		for j := range configured {
+			configuredRecord, err := DNSRecordsModelTransformation(configured[j : j+1])
+			if err == nil && sameDnsRecord(record[0], configuredRecord[0]) {
+				items[i] = configured[j]
+				break
+			}
+		}
+	}
+	return items
+}
+
+// dnsZoneResourceModelV0 maps the state of schema version 0, which stored
+// dns_records as a list and had none of the later attributes.
+type dnsZoneResourceModelV0 struct {
+	ID          types.String            `tfsdk:"id"`
+	Name        types.String            `tfsdk:"name"`
+	Records     []dnsRecordsItemModelV0 `tfsdk:"dns_records"`
+	Comment     types.String            `tfsdk:"comment"`
+	LastUpdated types.String            `tfsdk:"last_updated"`
+}
+
+type dnsRecordsItemModelV0 struct {
+	Name           types.String             `tfsdk:"name"`
+	Type           types.String             `tfsdk:"type"`
+	Data           types.String             `tfsdk:"data"`
+	ConfigRef      *configRefItemModel      `tfsdk:"config_ref"`
+	TargetGroupRef *targetGroupRefItemModel `tfsdk:"targetgroup_ref"`
+}
+
+// dnsZoneSchemaV0 returns the schema version 0 of the resource.
+func dnsZoneSchemaV0() schema.Schema {
+	refAttribute := schema.ObjectAttribute{
+		AttributeTypes: map[string]attr.Type{
+			"id": types.Int64Type,
+		},
+		Optional: true,
+	}
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"dns_records": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required: true,
+						},
+						"type": schema.StringAttribute{
+							Required: true,
+						},
+						"data": schema.StringAttribute{
+							Optional: true,
+						},
+						"config_ref":      refAttribute,
+						"targetgroup_ref": refAttribute,
+					},
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// UpgradeState migrates the state stored by the previous schema versions.
+func (r *dnsZoneResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	priorSchema := dnsZoneSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &priorSchema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var priorState dnsZoneResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				// The attributes added later get their defaults, zone_file is set by the next refresh.
+				upgradedState := dnsZoneResourceModel{
+					ID:                     priorState.ID,
+					Name:                   priorState.Name,
+					IgnoreUnmanagedRecords: types.BoolValue(false),
+					Comment:                priorState.Comment,
+					ZoneFile:               types.StringNull(),
+					LastUpdated:            priorState.LastUpdated,
+					Timeouts:               nullTimeouts(),
+				}
+				if priorState.Records != nil {
+					upgradedState.Records = []dnsRecordsItemModel{}
+				}
+				for _, record := range priorState.Records {
+					upgradedState.Records = append(upgradedState.Records, dnsRecordsItemModel{
+						Name:           record.Name,
+						Type:           record.Type,
+						Data:           record.Data,
+						ConfigRef:      record.ConfigRef,
+						TargetGroupRef: record.TargetGroupRef,
+					})
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgradedState)...)
+			},
+		},
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
-// Create a new resource.
 func (r *dnsZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan.
 	var plan dnsZoneResourceModel
@@ -282,8 +713,24 @@ func (r *dnsZoneResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 	// Check DNS zone existence.
-	if r.client.DnsZoneExist(plan.Name.ValueString()) {
+	exist, err := r.client.DnsZoneExist(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DNS zone",
+			fmt.Sprintf("Could not check DNS zone existence, unexpected error: %s", err.Error()),
+		)
+		return
+	}
+	if exist {
 		resp.Diagnostics.AddError(
 			"DNS zone has already exist!",
 			"Could not create DNS zone - DNS zone has already exist!",
@@ -292,7 +739,7 @@ func (r *dnsZoneResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	// Generate API request body from PLAN.
-	dnsRecords, err := r.DNSRecordsModelTransformation(plan.Records)
+	dnsRecords, err := DNSRecordsModelTransformation(plan.Records)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error while DNS zone Records creation and validation process",
@@ -315,17 +762,17 @@ func (r *dnsZoneResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	// Create new DNS zone.
-	createdDnszone, err := r.client.CreateDnsZone(dnsZone)
+	createdDnszone, err := r.client.CreateDnsZone(ctx, dnsZone)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating DNS zone",
-			fmt.Sprintf("Could not create DNS Zone, unexpected error: %s", err.Error()),
-		)
+		var recordsSet types.Set
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("dns_records"), &recordsSet)...)
+		addAPIErrorDiagnostics(&resp.Diagnostics, "Error creating DNS zone", "Could not create DNS Zone, unexpected error", err,
+			dnsZoneFieldPath(ctx, recordsSet, plan.Records))
 		return
 	}
 
 	// Map response body to schema and populate Computed attribute values.
-	dnsRecordsItems, err := r.DNSRecordsItemModelTransformation(createdDnszone.Records)
+	dnsRecordsItems, err := dnsRecordsState(createdDnszone.Records, dnsRecords, plan.IgnoreUnmanagedRecords, plan.Records)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error while DNS zone Records creation and validation process",
@@ -339,11 +786,11 @@ func (r *dnsZoneResource) Create(ctx context.Context, req resource.CreateRequest
 		createdZoneComment = createdDnszone.Comment
 	}
 	// Update state model from newly created DNS zone.
-	zoneId := r.client.GetDnsZoneIDByName(plan.Name.ValueString())
-	plan.ID = types.StringValue(strconv.Itoa(zoneId))
+	plan.ID = types.StringValue(strconv.Itoa(createdDnszone.ID))
 	plan.Name = types.StringValue(createdDnszone.Name)
 	plan.Records = dnsRecordsItems
 	plan.Comment = types.StringValue(createdZoneComment)
+	plan.ZoneFile = types.StringValue(formatZoneFile(createdDnszone.Name, createdDnszone.Records))
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	tflog.Trace(ctx, "DNS zone was created successfully!")
@@ -366,9 +813,31 @@ func (r *dnsZoneResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	zoneId, err := dnsZoneID(state.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Ngenix DNS zone", err.Error())
+		return
+	}
+
 	// Get refreshed DNS zone value from Ngenix.
-	zoneId := r.client.GetDnsZoneIDByName(state.Name.ValueString())
-	fromZone, err := r.client.GetDnsZoneById(zoneId)
+	fromZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+	if isNotFound(err) {
+		// DNS zone was deleted outside of Terraform, let Terraform re-create it.
+		resp.Diagnostics.AddWarning(
+			"Ngenix DNS zone not found",
+			fmt.Sprintf("DNS Zone with ID = %s was not found in Ngenix and is removed from the state, it will be re-created on the next apply.", state.ID.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Ngenix DNS zone",
@@ -377,8 +846,18 @@ func (r *dnsZoneResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	// Records known from the previous state are the records managed by this resource.
+	managedRecords, err := DNSRecordsModelTransformation(state.Records)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error while DNS zone Records creation and validation process",
+			fmt.Sprintf("Could not read DNS zone Records from the state, error: %s", err.Error()),
+		)
+		return
+	}
+
 	// Overwrite items with refreshed state.
-	dnsRecordsItems, err := r.DNSRecordsItemModelTransformation(fromZone.Records)
+	dnsRecordsItems, err := dnsRecordsState(fromZone.Records, managedRecords, state.IgnoreUnmanagedRecords, state.Records)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error while DNS zone Records creation and validation process",
@@ -389,6 +868,7 @@ func (r *dnsZoneResource) Read(ctx context.Context, req resource.ReadRequest, re
 	state.Name = types.StringValue(fromZone.Name)
 	state.Records = dnsRecordsItems
 	state.Comment = types.StringValue(fromZone.Comment)
+	state.ZoneFile = types.StringValue(formatZoneFile(fromZone.Name, fromZone.Records))
 
 	tflog.Trace(ctx, "DNS zone was read successfully!")
 
@@ -409,8 +889,22 @@ func (r *dnsZoneResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	zoneId, err := dnsZoneID(plan.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Ngenix DNS zones", err.Error())
+		return
+	}
+
 	// Generate API request body from plan.
-	dnsRecords, er := r.DNSRecordsModelTransformation(plan.Records)
+	dnsRecords, er := DNSRecordsModelTransformation(plan.Records)
 	if er != nil {
 		resp.Diagnostics.AddError(
 			"Error while DNS zone Records creation and validation process",
@@ -419,24 +913,62 @@ func (r *dnsZoneResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	// Records of other owners are read and written back in one go.
+	unlock := lockDnsZone(zoneId)
+	defer unlock()
+
+	// Keep records which are not managed by this resource.
+	requestRecords := dnsRecords
+	if plan.IgnoreUnmanagedRecords.ValueBool() {
+		var state dnsZoneResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		managedRecords, err := DNSRecordsModelTransformation(state.Records)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error while DNS zone Records creation and validation process",
+				fmt.Sprintf("Could not read DNS zone Records from the state, error: %s", err.Error()),
+			)
+			return
+		}
+		currentDnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading Ngenix DNS zone by ID",
+				fmt.Sprintf("Could not read Ngenix DNS zone by ID %d, error: %s", zoneId, err.Error()),
+			)
+			return
+		}
+		unmanagedRecords := filterDnsRecords(filterDnsRecords(currentDnsZone.Records, managedRecords, false), dnsRecords, false)
+		requestRecords = append(append([]restapi.Records{}, dnsRecords...), unmanagedRecords...)
+	}
+
 	var dnsZone = restapi.DnsZone{
-		Records: dnsRecords,
+		Records: requestRecords,
 		Comment: plan.Comment.ValueString(),
 	}
 
 	// Update existing DNS zone.
-	zoneId := r.client.GetDnsZoneIDByName(plan.Name.ValueString())
-	_, err := r.client.UpdateDnsZone(zoneId, dnsZone)
-	if err != nil {
+	_, err = r.client.UpdateDnsZone(ctx, zoneId, dnsZone)
+	if isNotFound(err) {
 		resp.Diagnostics.AddError(
-			"Error Updating Ngenix DNS zones",
-			fmt.Sprintf("Could not update DNS Zone (PATCH), unexpected error: %s", err.Error()),
+			"Ngenix DNS zone not found",
+			fmt.Sprintf("Could not update DNS Zone with ID = %d, it does not exist in Ngenix anymore. Refresh the state to re-create it.", zoneId),
 		)
 		return
 	}
+	if err != nil {
+		var recordsSet types.Set
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("dns_records"), &recordsSet)...)
+		addAPIErrorDiagnostics(&resp.Diagnostics, "Error Updating Ngenix DNS zones", "Could not update DNS Zone (PATCH), unexpected error", err,
+			dnsZoneFieldPath(ctx, recordsSet, plan.Records))
+		return
+	}
 
-	// Fetch updated traffic pattern.
-	updatedDnsZone, err := r.client.GetDnsZoneById(zoneId)
+	// Fetch updated DNS zone.
+	updatedDnsZone, err := r.client.GetDnsZoneById(ctx, zoneId)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Ngenix DNS zone by ID",
@@ -446,7 +978,7 @@ func (r *dnsZoneResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Update resource state with updated items and timestamp.
-	dnsRecordsItems, err := r.DNSRecordsItemModelTransformation(updatedDnsZone.Records)
+	dnsRecordsItems, err := dnsRecordsState(updatedDnsZone.Records, dnsRecords, plan.IgnoreUnmanagedRecords, plan.Records)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error while DNS zone Records creation and validation process",
@@ -454,11 +986,10 @@ func (r *dnsZoneResource) Update(ctx context.Context, req resource.UpdateRequest
 		)
 		return
 	}
-	updatedZoneId := r.client.GetDnsZoneIDByName(updatedDnsZone.Name)
-	plan.ID = types.StringValue(strconv.Itoa(updatedZoneId))
 	plan.Name = types.StringValue(updatedDnsZone.Name)
 	plan.Records = dnsRecordsItems
 	plan.Comment = types.StringValue(updatedDnsZone.Comment)
+	plan.ZoneFile = types.StringValue(formatZoneFile(updatedDnsZone.Name, updatedDnsZone.Records))
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	tflog.Trace(ctx, "DNS zone was updated successfully!")
@@ -479,9 +1010,27 @@ func (r *dnsZoneResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	zoneId, err := dnsZoneID(state.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Ngenix DNS zone", err.Error())
+		return
+	}
+
 	// Delete existing DNS zone
-	zoneId := r.client.GetDnsZoneIDByName(state.Name.ValueString())
-	err := r.client.DeleteDnsZone(zoneId)
+	err = r.client.DeleteDnsZone(ctx, zoneId)
+	if isNotFound(err) {
+		// DNS zone is already deleted, nothing to do.
+		tflog.Debug(ctx, "DNS zone was already deleted outside of Terraform", map[string]any{"id": zoneId})
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting Ngenix DNS zone",
@@ -497,10 +1046,15 @@ func (r *dnsZoneResource) ImportState(ctx context.Context, req resource.ImportSt
 	// Parse the ID from the import ID string. This usually contains the unique identifier for the DNS Zone.
 	resourceID := req.ID
 
+	// The imported state has no timeouts block yet, so the default read timeout is used.
+	ctx, cancel := context.WithTimeout(ctx, defaultReadTimeout)
+	defer cancel()
+
 	// Set the ID field in the state.
 	resp.Diagnostics.Append(
 		resp.State.Set(ctx, &dnsZoneResourceModel{
-			ID: types.StringValue(resourceID),
+			ID:       types.StringValue(resourceID),
+			Timeouts: nullTimeouts(),
 		})...,
 	)
 	if resp.Diagnostics.HasError() {
@@ -508,15 +1062,19 @@ func (r *dnsZoneResource) ImportState(ctx context.Context, req resource.ImportSt
 	}
 
 	// Fetch the DNS Zone by ID using the client.
-	dnsZoneInt, _ := strconv.Atoi(resourceID)
-	dnsZone, err := r.client.GetDnsZoneById(dnsZoneInt)
+	dnsZoneInt, err := dnsZoneID(types.StringValue(resourceID))
+	if err != nil {
+		resp.Diagnostics.AddError("Error importing resource", err.Error())
+		return
+	}
+	dnsZone, err := r.client.GetDnsZoneById(ctx, dnsZoneInt)
 	if err != nil {
 		resp.Diagnostics.AddError("Error fetching resource", fmt.Sprintf("Could not fetch DNS Zone with ID %s: %s", resourceID, err))
 		return
 	}
 
 	// Convert DNS Zone data to the resource model.
-	dnsRecordsItems, err := r.DNSRecordsItemModelTransformation(dnsZone.Records)
+	dnsRecordsItems, err := DNSRecordsItemModelTransformation(dnsZone.Records)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error while DNS zone Records creation and validation process",
@@ -526,11 +1084,14 @@ func (r *dnsZoneResource) ImportState(ctx context.Context, req resource.ImportSt
 	}
 	// Writing an updated / imported DNS model to the state.
 	state := dnsZoneResourceModel{
-		ID:          types.StringValue(resourceID),
-		Name:        types.StringValue(dnsZone.Name),
-		Records:     dnsRecordsItems,
-		Comment:     types.StringValue(dnsZone.Comment),
-		LastUpdated: types.StringValue(time.Now().Format(time.RFC850)),
+		ID:                     types.StringValue(resourceID),
+		Name:                   types.StringValue(dnsZone.Name),
+		Records:                dnsRecordsItems,
+		IgnoreUnmanagedRecords: types.BoolValue(false),
+		Comment:                types.StringValue(dnsZone.Comment),
+		ZoneFile:               types.StringValue(formatZoneFile(dnsZone.Name, dnsZone.Records)),
+		LastUpdated:            types.StringValue(time.Now().Format(time.RFC850)),
+		Timeouts:               nullTimeouts(),
 	}
 
 	// Set the state.
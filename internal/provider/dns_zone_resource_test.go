@@ -1,9 +1,19 @@
 package provider
 
 import (
+	"context"
+	"reflect"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func TestDnzZoneResource(t *testing.T) {
@@ -36,14 +46,17 @@ resource "ngenix_dnszone" "test" {
 					resource.TestCheckResourceAttr("ngenix_dnszone.test", "name", "ngenixterraformacctest.ru"),
 					// Verify number of items.
 					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "2"),
-					// Verify first dns record item.
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.0.name", "vm-a-record"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.0.type", "A"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.0.data", "23.12.76.128"),
-					// Verify second dns record item.
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.1.name", "config-ref-88903"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.1.type", "A"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.1.config_ref.id", "88903"),
+					// Verify dns record items regardless of their order.
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"name": "vm-a-record",
+						"type": "A",
+						"data": "23.12.76.128",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"name":          "config-ref-88903",
+						"type":          "A",
+						"config_ref.id": "88903",
+					}),
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("ngenix_dnszone.test", "id"),
 					resource.TestCheckResourceAttrSet("ngenix_dnszone.test", "last_updated"),
@@ -85,21 +98,509 @@ resource "ngenix_dnszone" "test" {
 }
 `,
 				Check: resource.ComposeAggregateTestCheckFunc(
-					// Verify first order item updated.
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.0.name", "vm-a-record"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.0.type", "A"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.0.data", "23.12.76.128"),
-					// Verify second dns record item.
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.1.name", "config-ref-88903"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.1.type", "A"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.1.config_ref.id", "88903"),
-					// Verify third dns record item updated.
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.2.name", "test-cname-record"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.2.type", "CNAME"),
-					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.2.data", "terraform-internal.express42.com."),
+					// Verify number of items.
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "3"),
+					// Verify dns record items regardless of their order.
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"name": "vm-a-record",
+						"type": "A",
+						"data": "23.12.76.128",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"name":          "config-ref-88903",
+						"type":          "A",
+						"config_ref.id": "88903",
+					}),
+					// Verify third dns record item added.
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"name": "test-cname-record",
+						"type": "CNAME",
+						"data": "terraform-internal.express42.com.",
+					}),
 				),
 			},
 			// Delete testing automatically occurs in TestCase.
 		},
 	})
 }
+
+func TestDnsZoneResourceDeletedOutOfBand(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	config := providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformoutofband.ru"
+  dns_records = [
+    {
+      name = "vm-a-record"
+      type = "A"
+      data = "23.12.76.128"
+    }
+  ]
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttrSet("ngenix_dnszone.test", "id"),
+			},
+			// DNS zone deleted in the Ngenix UI is planned for re-creation.
+			{
+				PreConfig:          func() { testAccAPI.deleteDnsZoneByName("ngenixterraformoutofband.ru") },
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Re-creation testing.
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "1"),
+			},
+		},
+	})
+}
+
+func TestDnsZoneResourceRename(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformrename.ru"
+}
+`,
+				Check: resource.TestCheckResourceAttr("ngenix_dnszone.test", "name", "ngenixterraformrename.ru"),
+			},
+			// Renaming a DNS zone re-creates it.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformrenamed.ru"
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ngenix_dnszone.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("ngenix_dnszone.test", "name", "ngenixterraformrenamed.ru"),
+			},
+		},
+	})
+}
+
+func TestDnsZoneResourceTimeouts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "timeouts.example"
+  dns_records = [
+    {
+      name = "www"
+      type = "A"
+      data = "192.0.2.1"
+    }
+  ]
+  timeouts {
+    create = "2m"
+    delete = "1m"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "timeouts.create", "2m"),
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "timeouts.delete", "1m"),
+					resource.TestCheckNoResourceAttr("ngenix_dnszone.test", "timeouts.update"),
+				),
+			},
+			// Invalid timeouts are rejected at plan time.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "timeouts.example"
+  timeouts {
+    update = "soon"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value"),
+			},
+		},
+	})
+}
+
+func TestDnsZoneResourceRecordsOrder(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformorder.ru"
+  dns_records = [
+    {
+      name = "a-record"
+      type = "A"
+      data = "23.12.76.128"
+    },
+    {
+      name = "b-record"
+      type = "A"
+      data = "23.12.76.129"
+    }
+  ]
+}
+`,
+				Check: resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "2"),
+			},
+			// Reordered records produce no changes.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformorder.ru"
+  dns_records = [
+    {
+      name = "b-record"
+      type = "A"
+      data = "23.12.76.129"
+    },
+    {
+      name = "a-record"
+      type = "A"
+      data = "23.12.76.128"
+    }
+  ]
+}
+`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestDnsZoneResourceTypedRecords(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformtyped.ru"
+  dns_records = [
+    {
+      name     = "@"
+      type     = "MX"
+      data     = "mail.ngenixterraformtyped.ru."
+      priority = 10
+    },
+    {
+      name     = "_sip._tcp"
+      type     = "SRV"
+      data     = "sip.ngenixterraformtyped.ru."
+      priority = 10
+      weight   = 5
+      port     = 5060
+    },
+    {
+      name  = "@"
+      type  = "CAA"
+      data  = "letsencrypt.org"
+      flags = 0
+      tag   = "issue"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_dnszone.test", "dns_records.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"type":     "MX",
+						"data":     "mail.ngenixterraformtyped.ru.",
+						"priority": "10",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"type":     "SRV",
+						"data":     "sip.ngenixterraformtyped.ru.",
+						"priority": "10",
+						"weight":   "5",
+						"port":     "5060",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+						"type":  "CAA",
+						"data":  "letsencrypt.org",
+						"flags": "0",
+						"tag":   "issue",
+					}),
+				),
+			},
+			// ImportState testing, the type specific fields are parsed from data.
+			{
+				ResourceName:            "ngenix_dnszone.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Hand-packed data of the same records produces no changes.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformtyped.ru"
+  dns_records = [
+    {
+      name = "@"
+      type = "MX"
+      data = "10 mail.ngenixterraformtyped.ru."
+    },
+    {
+      name = "_sip._tcp"
+      type = "SRV"
+      data = "10 5 5060 sip.ngenixterraformtyped.ru."
+    },
+    {
+      name = "@"
+      type = "CAA"
+      data = "0 issue \"letsencrypt.org\""
+    }
+  ]
+}
+`,
+				Check: resource.TestCheckTypeSetElemNestedAttrs("ngenix_dnszone.test", "dns_records.*", map[string]string{
+					"type": "MX",
+					"data": "10 mail.ngenixterraformtyped.ru.",
+				}),
+			},
+		},
+	})
+}
+
+func TestDnsZoneResourceValidateConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// IPv4 address is not accepted for AAAA records.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformvalidate.ru"
+  dns_records = [
+    {
+      name = "v6"
+      type = "AAAA"
+      data = "23.12.76.128"
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is not a valid IPv6 address`),
+			},
+			// TXT strings are limited to 255 bytes.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformvalidate.ru"
+  dns_records = [
+    {
+      name = "spf"
+      type = "TXT"
+      data = "${join("", [for i in range(300) : "a"])}"
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`TXT record data is 300 bytes long`),
+			},
+			// CNAME records are not allowed at the zone apex.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformvalidate.ru"
+  dns_records = [
+    {
+      name = "@"
+      type = "CNAME"
+      data = "ngenixterraform.ru."
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`CNAME record is not allowed at the zone apex`),
+			},
+			// CNAME records could not coexist with other records of the same name.
+			{
+				Config: providerConfig + `
+resource "ngenix_dnszone" "test" {
+  name = "ngenixterraformvalidate.ru"
+  dns_records = [
+    {
+      name = "www"
+      type = "CNAME"
+      data = "ngenixterraform.ru."
+    },
+    {
+      name = "www"
+      type = "TXT"
+      data = "text"
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`CNAME record www can not coexist with other records`),
+			},
+		},
+	})
+}
+
+func TestDnsRecordsTransformation(t *testing.T) {
+	records := []dnsRecordsItemModel{
+		{Name: types.StringValue("@"), Type: types.StringValue("MX"), Data: types.StringValue("mail.example.com."), Priority: types.Int64Value(10)},
+		{Name: types.StringValue("_sip._tcp"), Type: types.StringValue("SRV"), Data: types.StringValue("sip.example.com."), Priority: types.Int64Value(10), Weight: types.Int64Value(5), Port: types.Int64Value(5060)},
+		{Name: types.StringValue("@"), Type: types.StringValue("CAA"), Data: types.StringValue("letsencrypt.org"), Flags: types.Int64Value(128), Tag: types.StringValue("issue")},
+		{Name: types.StringValue("www"), Type: types.StringValue("CNAME"), Data: types.StringValue("example.com.")},
+	}
+	expectedData := []string{"10 mail.example.com.", "10 5 5060 sip.example.com.", `128 issue "letsencrypt.org"`, "example.com."}
+
+	apiRecords, err := DNSRecordsModelTransformation(records)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, apiRecord := range apiRecords {
+		if apiRecord.Data != expectedData[i] {
+			t.Errorf("record %d: expected data %q, got %q", i, expectedData[i], apiRecord.Data)
+		}
+	}
+
+	items, err := DNSRecordsItemModelTransformation(apiRecords)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, item := range items {
+		if item != records[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, records[i], item)
+		}
+	}
+
+	invalid := []dnsRecordsItemModel{
+		{Name: types.StringValue("www"), Type: types.StringValue("CNAME"), Data: types.StringValue("example.com."), Priority: types.Int64Value(10)},
+		{Name: types.StringValue("@"), Type: types.StringValue("MX"), Data: types.StringValue("mail.example.com."), Port: types.Int64Value(25)},
+		{Name: types.StringValue("_sip._tcp"), Type: types.StringValue("SRV"), Data: types.StringValue("sip.example.com."), Priority: types.Int64Value(10)},
+		{Name: types.StringValue("@"), Type: types.StringValue("CAA"), Data: types.StringValue("letsencrypt.org"), Tag: types.StringValue("issue")},
+		{Name: types.StringValue("@"), Type: types.StringValue("CAA"), Data: types.StringValue("letsencrypt.org"), Flags: types.Int64Value(0), Tag: types.StringValue("contactemail")},
+		{Name: types.StringValue("@"), Type: types.StringValue("MX"), Data: types.StringValue("mail.example.com."), Priority: types.Int64Value(70000)},
+	}
+	for i, record := range invalid {
+		if _, err := DNSRecordsModelTransformation([]dnsRecordsItemModel{record}); err == nil {
+			t.Errorf("invalid record %d: expected an error", i)
+		}
+	}
+}
+
+// dnsZoneStateV0 is the ngenix_dnszone state written with schema version 0.
+const dnsZoneStateV0 = `{
+  "id": "6184123",
+  "name": "ngenixterraformacctest.ru",
+  "comment": "Changed by Terraform",
+  "last_updated": "Monday, 02-Jan-06 15:04:05 MST",
+  "dns_records": [
+    {"name": "vm-a-record", "type": "A", "data": "23.12.76.128", "config_ref": null, "targetgroup_ref": null},
+    {"name": "config-ref-88903", "type": "A", "data": null, "config_ref": {"id": 88903}, "targetgroup_ref": null}
+  ]
+}`
+
+func TestDnsZoneResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	server := providerserver.NewProtocol6(New("test")())()
+
+	var schemaResp fwresource.SchemaResponse
+	(&dnsZoneResource{}).Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	stateType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	upgradeResp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "ngenix_dnszone",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(dnsZoneStateV0)},
+	})
+	if err != nil || len(upgradeResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected error upgrading state: %v, %v", err, upgradeResp.Diagnostics)
+	}
+	upgradedValue, err := upgradeResp.UpgradedState.Unmarshal(stateType)
+	if err != nil {
+		t.Fatalf("unexpected error decoding upgraded state: %s", err)
+	}
+
+	var upgraded dnsZoneResourceModel
+	if diags := (tfsdk.State{Schema: schemaResp.Schema, Raw: upgradedValue}).Get(ctx, &upgraded); diags.HasError() {
+		t.Fatalf("unexpected error reading upgraded state: %v", diags)
+	}
+	if upgraded.ID.ValueString() != "6184123" || upgraded.Name.ValueString() != "ngenixterraformacctest.ru" || upgraded.IgnoreUnmanagedRecords.ValueBool() {
+		t.Fatalf("unexpected upgraded state: %+v", upgraded)
+	}
+	expected := []dnsRecordsItemModel{
+		{Name: types.StringValue("vm-a-record"), Type: types.StringValue("A"), Data: types.StringValue("23.12.76.128")},
+		{Name: types.StringValue("config-ref-88903"), Type: types.StringValue("A"), ConfigRef: &configRefItemModel{ID: types.Int64Value(88903)}},
+	}
+	if len(upgraded.Records) != len(expected) {
+		t.Fatalf("expected %d records, got: %+v", len(expected), upgraded.Records)
+	}
+	for _, record := range expected {
+		found := false
+		for _, upgradedRecord := range upgraded.Records {
+			if reflect.DeepEqual(upgradedRecord, record) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("record %s is missing in the upgraded state: %+v", record.Name.ValueString(), upgraded.Records)
+		}
+	}
+
+	// The unchanged configuration of version 0 plans no changes.
+	config, err := tftypes.Transform(upgradedValue, func(attributePath *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		if steps := attributePath.Steps(); len(steps) == 1 {
+			switch steps[0] {
+			case tftypes.AttributeName("id"), tftypes.AttributeName("last_updated"), tftypes.AttributeName("zone_file"), tftypes.AttributeName("ignore_unmanaged_records"):
+				return tftypes.NewValue(value.Type(), nil), nil
+			}
+		}
+		return value, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error building config: %s", err)
+	}
+	configValue, err := tfprotov6.NewDynamicValue(stateType, config)
+	if err != nil {
+		t.Fatalf("unexpected error encoding config: %s", err)
+	}
+	planResp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "ngenix_dnszone",
+		PriorState:       upgradeResp.UpgradedState,
+		ProposedNewState: upgradeResp.UpgradedState,
+		Config:           &configValue,
+	})
+	if err != nil || len(planResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected error planning: %v, %v", err, planResp.Diagnostics)
+	}
+	if len(planResp.RequiresReplace) > 0 {
+		t.Errorf("expected no replacement, got: %v", planResp.RequiresReplace)
+	}
+	plannedValue, err := planResp.PlannedState.Unmarshal(stateType)
+	if err != nil {
+		t.Fatalf("unexpected error decoding planned state: %s", err)
+	}
+	if !plannedValue.Equal(upgradedValue) {
+		diffs, _ := plannedValue.Diff(upgradedValue)
+		t.Errorf("expected no changes, got: %v", diffs)
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &dnsZoneSingleDataSource{}
+	_ datasource.DataSourceWithConfigure = &dnsZoneSingleDataSource{}
+)
+
+// DnsZoneSingleDataSource is a helper function to simplify the provider implementation.
+func DnsZoneSingleDataSource() datasource.DataSource {
+	return &dnsZoneSingleDataSource{}
+}
+
+// dnsZoneSingleDataSource looks up a single DNS zone, its model is the dnsZoneModel
+// of the ngenix_dns_zones elements.
+type dnsZoneSingleDataSource struct {
+	client *ngenixClient
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *dnsZoneSingleDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ngenixClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *dnsZoneSingleDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_zone"
+}
+
+func (d *dnsZoneSingleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a single DNS zone by ID or name.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "DNS zone ID. Exactly one of id and name should be set.",
+				Validators: []validator.Int64{
+					int64validator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "DNS zone name. Exactly one of id and name should be set.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"dns_records":     dnsZoneRecordsSchema(),
+			"records_by_name": dnsZoneRecordsByNameSchema(),
+			"comment": schema.StringAttribute{
+				Computed:    true,
+				Description: "A comment string.",
+			},
+		},
+	}
+}
+
+func (d *dnsZoneSingleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config dnsZoneModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	attributePath, dnsZoneId := path.Root("id"), int(config.Id.ValueInt64())
+	if config.Id.IsNull() {
+		attributePath = path.Root("name")
+		dnsZones, err := d.client.GetAllDnsZonesList(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Ngenix DNS zone",
+				fmt.Sprintf("Could not read Ngenix DNS zones, error: %s", err.Error()),
+			)
+			return
+		}
+		found, err := findDnsZoneByName(dnsZones, config.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(attributePath, "DNS zone not found", err.Error())
+			return
+		}
+		dnsZoneId = found.ID
+	}
+	dnszone, err := d.client.GetDnsZoneById(ctx, dnsZoneId)
+	if isNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			attributePath,
+			"DNS zone not found",
+			fmt.Sprintf("DNS zone with ID = %d was not found in Ngenix.", dnsZoneId),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix DNS zone",
+			fmt.Sprintf("Could not read Ngenix DNS zone by ID = %d, error: %s", dnsZoneId, err.Error()),
+		)
+		return
+	}
+
+	// Map response body to model.
+	state, err := newDnsZoneModel(*dnszone, dnsRecordFilter{})
+	if err != nil {
+		resp.Diagnostics.AddError("Bad input data format", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "DNS zone was read successfully!")
+
+	// Set state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// findDnsZoneByName returns the DNS zone with the name, the name should be unique.
+func findDnsZoneByName(dnsZones []restapi.DnsZone, name string) (*restapi.DnsZone, error) {
+	var found *restapi.DnsZone
+	for i, dnsZone := range dnsZones {
+		if dnsZone.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("several DNS zones are named %s, look it up by id", name)
+		}
+		found = &dnsZones[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("DNS zone with name = %s was not found in Ngenix", name)
+	}
+	return found, nil
+}
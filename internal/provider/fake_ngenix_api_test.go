@@ -0,0 +1,530 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+
+	"ngenix/restapi"
+)
+
+// Credentials accepted by the fake Ngenix API for the test customer.
+const (
+	fakeUsername   = "terraform@example.com/token"
+	fakePassword   = "terraform-test-token"
+	fakeCustomerId = 21046
+)
+
+// fakeNgenixAPI is a stateful in-memory stand-in for the DNS zone and
+// traffic pattern endpoints of the Ngenix platform API. Acceptance tests
+// point the provider host at it, so they run offline and deterministically.
+// The routes, the field names and the error body are assumed, they are not
+// taken from the Ngenix API specification, so the tests do not prove the
+// provider works with the real API.
+type fakeNgenixAPI struct {
+	server *httptest.Server
+
+	mu              sync.Mutex
+	nextId          int
+	customers       map[string]int
+	dnsZones        map[int]*fakeDnsZone
+	trafficPatterns map[int]*fakeTrafficPattern
+}
+
+type fakeRef struct {
+	ID int `json:"id"`
+}
+
+type fakeDnsRecord struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Data           string   `json:"data,omitempty"`
+	ConfigRef      *fakeRef `json:"configRef,omitempty"`
+	TargetGroupRef *fakeRef `json:"targetGroupRef,omitempty"`
+}
+
+type fakeDnsZone struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	CustomerRef fakeRef         `json:"customerRef"`
+	Records     []fakeDnsRecord `json:"records"`
+	Comment     string          `json:"comment"`
+}
+
+type fakePattern struct {
+	Addr          *string `json:"addr,omitempty"`
+	CommonString  *string `json:"commonString,omitempty"`
+	CountryCode   *string `json:"countryCode,omitempty"`
+	HttpMethod    *string `json:"httpMethod,omitempty"`
+	Asn           *int64  `json:"asn,omitempty"`
+	Md5HashString *string `json:"md5HashString,omitempty"`
+	Ttl           *int64  `json:"ttl,omitempty"`
+	Expires       *int64  `json:"expires,omitempty"`
+	Comment       string  `json:"comment"`
+}
+
+type fakeTrafficPattern struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	ContentType string        `json:"contentType"`
+	CustomerRef fakeRef       `json:"customerRef"`
+	Patterns    []fakePattern `json:"patterns"`
+}
+
+// fakeFieldError describes a single rejected field of a request body.
+type fakeFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// fakeError is the error body returned for every non-2xx response, the shape
+// is assumed and not verified against the real API.
+type fakeError struct {
+	Code    string           `json:"code"`
+	Message string           `json:"message"`
+	Errors  []fakeFieldError `json:"errors,omitempty"`
+}
+
+// newFakeNgenixAPI starts the fake API server seeded with fixtures for the
+// test customer and for one foreign customer whose objects must never leak.
+func newFakeNgenixAPI() *fakeNgenixAPI {
+	f := &fakeNgenixAPI{
+		nextId: 1000,
+		customers: map[string]int{
+			fakeUsername:                fakeCustomerId,
+			"someone@example.com/token": 10001,
+		},
+		dnsZones:        map[int]*fakeDnsZone{},
+		trafficPatterns: map[int]*fakeTrafficPattern{},
+	}
+	f.seed()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /customer", f.getCustomer)
+	mux.HandleFunc("GET /dns-zone", f.listDnsZones)
+	mux.HandleFunc("POST /dns-zone", f.createDnsZone)
+	mux.HandleFunc("GET /dns-zone/{id}", f.getDnsZone)
+	mux.HandleFunc("PATCH /dns-zone/{id}", f.patchDnsZone)
+	mux.HandleFunc("DELETE /dns-zone/{id}", f.deleteDnsZone)
+	mux.HandleFunc("GET /traffic-pattern", f.listTrafficPatterns)
+	mux.HandleFunc("POST /traffic-pattern", f.createTrafficPattern)
+	mux.HandleFunc("GET /traffic-pattern/{id}", f.getTrafficPattern)
+	mux.HandleFunc("PATCH /traffic-pattern/{id}", f.patchTrafficPattern)
+	mux.HandleFunc("DELETE /traffic-pattern/{id}", f.deleteTrafficPattern)
+
+	f.server = httptest.NewServer(f.authenticate(http.StripPrefix("/api/v3", mux)))
+	return f
+}
+
+// URL returns the API base URL to be used as the provider host.
+func (f *fakeNgenixAPI) URL() string {
+	return f.server.URL + "/api/v3/"
+}
+
+// Close shuts the server down.
+func (f *fakeNgenixAPI) Close() {
+	f.server.Close()
+}
+
+// deleteDnsZoneByName removes a DNS zone the way a user would do it in the
+// Ngenix UI, outside of Terraform.
+func (f *fakeNgenixAPI) deleteDnsZoneByName(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for id, zone := range f.dnsZones {
+		if zone.Name == name {
+			delete(f.dnsZones, id)
+		}
+	}
+}
+
+// deleteTrafficPatternByName removes a traffic pattern outside of Terraform.
+func (f *fakeNgenixAPI) deleteTrafficPatternByName(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for id, trafficPattern := range f.trafficPatterns {
+		if trafficPattern.Name == name {
+			delete(f.trafficPatterns, id)
+		}
+	}
+}
+
+// expireTrafficPatternPattern removes the pattern with the value from the
+// traffic pattern the way Ngenix does it when the pattern ttl or expires lapses.
+func (f *fakeNgenixAPI) expireTrafficPatternPattern(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, trafficPattern := range f.trafficPatterns {
+		if trafficPattern.Name != name {
+			continue
+		}
+		for i, pattern := range trafficPattern.Patterns {
+			if fakePatternValue(pattern) == value {
+				trafficPattern.Patterns = append(trafficPattern.Patterns[:i], trafficPattern.Patterns[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (f *fakeNgenixAPI) seed() {
+	addr := "10.0.0.0/8"
+	blocked := "192.0.2.0/24"
+	get := "GET"
+
+	f.dnsZones[1] = &fakeDnsZone{
+		ID: 1, Name: "seed-one.example", CustomerRef: fakeRef{ID: fakeCustomerId}, Comment: "Seeded",
+		Records: []fakeDnsRecord{{Name: "www", Type: "A", Data: "192.0.2.10"}},
+	}
+	f.dnsZones[2] = &fakeDnsZone{
+		ID: 2, Name: "seed-two.example", CustomerRef: fakeRef{ID: fakeCustomerId}, Comment: "Seeded",
+		Records: []fakeDnsRecord{{Name: "cdn", Type: "A", ConfigRef: &fakeRef{ID: 88903}}},
+	}
+	f.dnsZones[3] = &fakeDnsZone{
+		ID: 3, Name: "foreign.example", CustomerRef: fakeRef{ID: 10001}, Comment: "Foreign",
+	}
+
+	f.trafficPatterns[11] = &fakeTrafficPattern{
+		ID: 11, Name: "seed-commonlist", Type: "commonlist", ContentType: "addr",
+		CustomerRef: fakeRef{ID: fakeCustomerId}, Patterns: []fakePattern{{Addr: &addr}},
+	}
+	f.trafficPatterns[12] = &fakeTrafficPattern{
+		ID: 12, Name: "seed-blacklist", Type: "blacklist", ContentType: "addr",
+		CustomerRef: fakeRef{ID: fakeCustomerId}, Patterns: []fakePattern{{Addr: &blocked}},
+	}
+	f.trafficPatterns[13] = &fakeTrafficPattern{
+		ID: 13, Name: "foreign-commonlist", Type: "commonlist", ContentType: "httpMethod",
+		CustomerRef: fakeRef{ID: 10001}, Patterns: []fakePattern{{HttpMethod: &get}},
+	}
+}
+
+type fakeCustomerKey struct{}
+
+// authenticate checks basic auth credentials and stores the customer ID of
+// the caller in the request context.
+func (f *fakeNgenixAPI) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		customerId, known := f.customers[username]
+		if !ok || !known || password == "" {
+			writeFakeError(w, http.StatusUnauthorized, "unauthorized", "invalid credentials")
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), fakeCustomerKey{}, customerId))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func fakeCustomer(r *http.Request) int {
+	customerId, _ := r.Context().Value(fakeCustomerKey{}).(int)
+	return customerId
+}
+
+func (f *fakeNgenixAPI) getCustomer(w http.ResponseWriter, r *http.Request) {
+	writeFakeJSON(w, http.StatusOK, map[string]any{
+		"elements": []fakeRef{{ID: fakeCustomer(r)}},
+	})
+}
+
+func (f *fakeNgenixAPI) listDnsZones(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zones := []*fakeDnsZone{}
+	for _, id := range sortedKeys(f.dnsZones) {
+		if zone := f.dnsZones[id]; zone.CustomerRef.ID == fakeCustomer(r) {
+			zones = append(zones, zone)
+		}
+	}
+	writeFakeJSON(w, http.StatusOK, map[string]any{"elements": zones})
+}
+
+func (f *fakeNgenixAPI) createDnsZone(w http.ResponseWriter, r *http.Request) {
+	var zone fakeDnsZone
+	if err := json.NewDecoder(r.Body).Decode(&zone); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if zone.Name == "" {
+		writeFakeValidationError(w, fakeFieldError{Field: "name", Message: "must not be empty"})
+		return
+	}
+	for _, existing := range f.dnsZones {
+		if existing.Name == zone.Name {
+			writeFakeValidationError(w, fakeFieldError{Field: "name", Message: "DNS zone already exists"})
+			return
+		}
+	}
+	if fieldErrors := validateFakeDnsRecords(zone.Records); len(fieldErrors) > 0 {
+		writeFakeValidationError(w, fieldErrors...)
+		return
+	}
+
+	f.nextId++
+	zone.ID = f.nextId
+	zone.CustomerRef = fakeRef{ID: fakeCustomer(r)}
+	if zone.Records == nil {
+		zone.Records = []fakeDnsRecord{}
+	}
+	f.dnsZones[zone.ID] = &zone
+	writeFakeJSON(w, http.StatusCreated, zone)
+}
+
+func (f *fakeNgenixAPI) getDnsZone(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zone, ok := f.lookupDnsZone(w, r)
+	if !ok {
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, zone)
+}
+
+func (f *fakeNgenixAPI) patchDnsZone(w http.ResponseWriter, r *http.Request) {
+	var patch struct {
+		Name    *string          `json:"name"`
+		Records *[]fakeDnsRecord `json:"records"`
+		Comment *string          `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zone, ok := f.lookupDnsZone(w, r)
+	if !ok {
+		return
+	}
+	// Only the fields present in the body are changed, empty values are
+	// treated as absent the same way the platform does.
+	if patch.Records != nil {
+		if fieldErrors := validateFakeDnsRecords(*patch.Records); len(fieldErrors) > 0 {
+			writeFakeValidationError(w, fieldErrors...)
+			return
+		}
+		zone.Records = *patch.Records
+	}
+	if patch.Name != nil && *patch.Name != "" {
+		zone.Name = *patch.Name
+	}
+	if patch.Comment != nil && *patch.Comment != "" {
+		zone.Comment = *patch.Comment
+	}
+	writeFakeJSON(w, http.StatusOK, zone)
+}
+
+func (f *fakeNgenixAPI) deleteDnsZone(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zone, ok := f.lookupDnsZone(w, r)
+	if !ok {
+		return
+	}
+	delete(f.dnsZones, zone.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupDnsZone returns the zone addressed by the request path, replying
+// with 404 when it does not exist or belongs to another customer.
+func (f *fakeNgenixAPI) lookupDnsZone(w http.ResponseWriter, r *http.Request) (*fakeDnsZone, bool) {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	zone, ok := f.dnsZones[id]
+	if !ok || zone.CustomerRef.ID != fakeCustomer(r) {
+		writeFakeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("DNS zone %s not found", r.PathValue("id")))
+		return nil, false
+	}
+	return zone, true
+}
+
+func validateFakeDnsRecords(records []fakeDnsRecord) []fakeFieldError {
+	fieldErrors := []fakeFieldError{}
+	for i, record := range records {
+		if !isDnsTypeInRange(record.Type, DnsRecordTypes) {
+			fieldErrors = append(fieldErrors, fakeFieldError{
+				Field:   fmt.Sprintf("records[%d].type", i),
+				Message: "unsupported record type",
+			})
+		}
+		if record.Data == "" && record.ConfigRef == nil && record.TargetGroupRef == nil {
+			fieldErrors = append(fieldErrors, fakeFieldError{
+				Field:   fmt.Sprintf("records[%d].data", i),
+				Message: "must not be empty",
+			})
+		}
+	}
+	return fieldErrors
+}
+
+func (f *fakeNgenixAPI) listTrafficPatterns(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trafficPatterns := []*fakeTrafficPattern{}
+	for _, id := range sortedKeys(f.trafficPatterns) {
+		if trafficPattern := f.trafficPatterns[id]; trafficPattern.CustomerRef.ID == fakeCustomer(r) {
+			trafficPatterns = append(trafficPatterns, trafficPattern)
+		}
+	}
+	writeFakeJSON(w, http.StatusOK, map[string]any{"elements": trafficPatterns})
+}
+
+func (f *fakeNgenixAPI) createTrafficPattern(w http.ResponseWriter, r *http.Request) {
+	var trafficPattern fakeTrafficPattern
+	if err := json.NewDecoder(r.Body).Decode(&trafficPattern); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fieldErrors := []fakeFieldError{}
+	if trafficPattern.Name == "" {
+		fieldErrors = append(fieldErrors, fakeFieldError{Field: "name", Message: "must not be empty"})
+	}
+	if !restapi.IsValueInRange(trafficPattern.Type, TrafficPatternTypes) || restapi.IsValueInRange(trafficPattern.Type, TrafficPatternROTypes) {
+		fieldErrors = append(fieldErrors, fakeFieldError{Field: "type", Message: "unsupported traffic pattern type"})
+	}
+	if !restapi.IsValueInRange(trafficPattern.ContentType, TrafficPatternContentTypes) {
+		fieldErrors = append(fieldErrors, fakeFieldError{Field: "contentType", Message: "unsupported content type"})
+	}
+	if len(fieldErrors) > 0 {
+		writeFakeValidationError(w, fieldErrors...)
+		return
+	}
+
+	f.nextId++
+	trafficPattern.ID = f.nextId
+	trafficPattern.CustomerRef = fakeRef{ID: fakeCustomer(r)}
+	if trafficPattern.Patterns == nil {
+		trafficPattern.Patterns = []fakePattern{}
+	}
+	f.trafficPatterns[trafficPattern.ID] = &trafficPattern
+	writeFakeJSON(w, http.StatusCreated, trafficPattern)
+}
+
+func (f *fakeNgenixAPI) getTrafficPattern(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trafficPattern, ok := f.lookupTrafficPattern(w, r)
+	if !ok {
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, trafficPattern)
+}
+
+func (f *fakeNgenixAPI) patchTrafficPattern(w http.ResponseWriter, r *http.Request) {
+	var patch struct {
+		Name     *string        `json:"name"`
+		Patterns *[]fakePattern `json:"patterns"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trafficPattern, ok := f.lookupTrafficPattern(w, r)
+	if !ok {
+		return
+	}
+	if restapi.IsValueInRange(trafficPattern.Type, TrafficPatternROTypes) {
+		writeFakeError(w, http.StatusForbidden, "forbidden", fmt.Sprintf("traffic pattern type %s is read-only", trafficPattern.Type))
+		return
+	}
+	if patch.Name != nil && *patch.Name != "" {
+		trafficPattern.Name = *patch.Name
+	}
+	if patch.Patterns != nil {
+		trafficPattern.Patterns = *patch.Patterns
+	}
+	writeFakeJSON(w, http.StatusOK, trafficPattern)
+}
+
+// fakePatternValue returns the value of the pattern whatever its content type is.
+func fakePatternValue(pattern fakePattern) string {
+	for _, value := range []*string{pattern.Addr, pattern.CommonString, pattern.CountryCode, pattern.HttpMethod, pattern.Md5HashString} {
+		if value != nil {
+			return *value
+		}
+	}
+	if pattern.Asn != nil {
+		return strconv.FormatInt(*pattern.Asn, 10)
+	}
+	return ""
+}
+
+func (f *fakeNgenixAPI) deleteTrafficPattern(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trafficPattern, ok := f.lookupTrafficPattern(w, r)
+	if !ok {
+		return
+	}
+	delete(f.trafficPatterns, trafficPattern.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupTrafficPattern returns the traffic pattern addressed by the request
+// path, replying with 404 when it does not exist or belongs to another customer.
+func (f *fakeNgenixAPI) lookupTrafficPattern(w http.ResponseWriter, r *http.Request) (*fakeTrafficPattern, bool) {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	trafficPattern, ok := f.trafficPatterns[id]
+	if !ok || trafficPattern.CustomerRef.ID != fakeCustomer(r) {
+		writeFakeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("traffic pattern %s not found", r.PathValue("id")))
+		return nil, false
+	}
+	return trafficPattern, true
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, code, message string) {
+	writeFakeJSON(w, status, fakeError{Code: code, Message: message})
+}
+
+func writeFakeValidationError(w http.ResponseWriter, fieldErrors ...fakeFieldError) {
+	writeFakeJSON(w, http.StatusBadRequest, fakeError{
+		Code:    "validation_error",
+		Message: "request validation failed",
+		Errors:  fieldErrors,
+	})
+}
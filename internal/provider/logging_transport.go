@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// httpLogSubsystem is the tflog subsystem of the Ngenix API requests, its level
+// is set by the TF_LOG_PROVIDER_NGENIX_HTTP environment variable.
+const httpLogSubsystem = "ngenix_http"
+
+// maxLoggedBodySize limits the request and response bodies written to the logs.
+const maxLoggedBodySize = 4096
+
+// maskedHeaders lists the headers whose values are never written to the logs.
+var maskedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// loggingTransport is the http.RoundTripper of the Ngenix API client logging
+// every attempt of a request to the ngenix_http subsystem: the method, URL,
+// status code and duration at DEBUG, the headers and truncated bodies at TRACE.
+// Every attempt gets a request ID matching its log entries, it is not sent to
+// Ngenix. The password and the authorization headers are masked.
+type loggingTransport struct {
+	next     http.RoundTripper
+	password string
+}
+
+// newLoggingTransport wraps the next transport, http.DefaultTransport is used if it is nil.
+func newLoggingTransport(next http.RoundTripper, password string) *loggingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{next: next, password: password}
+}
+
+// RoundTrip sends the request and logs it with the response.
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), httpLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_NGENIX_HTTP"))
+	if t.password != "" {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, httpLogSubsystem, t.password)
+	}
+	ctx = tflog.SubsystemSetField(ctx, httpLogSubsystem, "ngenix_request_id", newRequestId())
+
+	requestBody, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Sending Ngenix API request", map[string]any{
+		"http_method": req.Method,
+		"http_url":    req.URL.Redacted(),
+	})
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "Ngenix API request details", map[string]any{
+		"http_request_headers": loggedHeaders(req.Header),
+		"http_request_body":    truncateBody(requestBody, int64(len(requestBody))),
+	})
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, httpLogSubsystem, "Ngenix API request failed", map[string]any{
+			"http_method":      req.Method,
+			"http_url":         req.URL.Redacted(),
+			"http_duration_ms": duration.Milliseconds(),
+			"error":            err.Error(),
+		})
+		return nil, err
+	}
+
+	// Only the logged part of the body is read, it is put back before the unread rest.
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodySize+1))
+	resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(responseBody), resp.Body), Closer: resp.Body}
+	fields := map[string]any{
+		"http_method":      req.Method,
+		"http_url":         req.URL.Redacted(),
+		"http_status_code": resp.StatusCode,
+		"http_duration_ms": duration.Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Received Ngenix API response", fields)
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "Ngenix API response details", map[string]any{
+		"http_response_headers": loggedHeaders(resp.Header),
+		"http_response_body":    truncateBody(responseBody, resp.ContentLength),
+	})
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// prefixedBody is the response body with its read part put back.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// peekRequestBody returns the body of the request, leaving it unread.
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		read, err := io.ReadAll(body)
+		_ = body.Close()
+		return read, err
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// loggedHeaders returns the headers to log with the secrets masked.
+func loggedHeaders(header http.Header) map[string]string {
+	logged := make(map[string]string, len(header))
+	for key, values := range header {
+		if maskedHeaders[http.CanonicalHeaderKey(key)] {
+			logged[key] = "***"
+			continue
+		}
+		logged[key] = strings.Join(values, ", ")
+	}
+	return logged
+}
+
+// truncateBody returns the body to log, limited by maxLoggedBodySize. The size
+// is the length of the whole body, -1 if it is unknown.
+func truncateBody(body []byte, size int64) string {
+	if len(body) <= maxLoggedBodySize {
+		return string(body)
+	}
+	if size < 0 {
+		return fmt.Sprintf("%s... (truncated)", body[:maxLoggedBodySize])
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", body[:maxLoggedBodySize], size-maxLoggedBodySize)
+}
+
+// newRequestId returns a random ID of the request.
+func newRequestId() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ngenixClient is the Ngenix API client shared by the resources and data sources.
+// It has context-aware variants of the core library methods used by the provider:
+// every request is sent with the context of the Terraform operation, so it is
+// canceled on Ctrl-C or when the operation times out, and the log fields of the
+// context and of the provider reach the transport logs. The requests are sent
+// by coreLibraryTransport, one core library call at a time.
+type ngenixClient struct {
+	client *restapi.Client
+	// transport sends the requests of the calls, e.g. with retries.
+	transport http.RoundTripper
+	// logFields are added to the context of every request, e.g. the Ngenix API host.
+	logFields map[string]any
+}
+
+// newNgenixClient wraps the core library client configured by the provider,
+// the requests are sent by the replaced http.DefaultTransport if the transport is nil.
+func newNgenixClient(client *restapi.Client, transport http.RoundTripper, logFields map[string]any) *ngenixClient {
+	next := installCoreLibraryTransport()
+	if transport == nil {
+		transport = next
+	}
+	return &ngenixClient{client: client, transport: transport, logFields: logFields}
+}
+
+// with calls f with the core library client sending its requests with the
+// context extended by the log fields of the client and the name of the called
+// method. The returned transport has recorded the error responses of the call.
+func (c *ngenixClient) with(ctx context.Context, method string, f func(client *restapi.Client)) (*apiErrorTransport, error) {
+	ctx = tflog.SetField(ctx, "ngenix_client_method", method)
+	for key, value := range c.logFields {
+		ctx = tflog.SetField(ctx, key, value)
+	}
+	recorder := &apiErrorTransport{next: c.transport}
+	release, err := coreLibraryTransport.begin(ctx, recorder)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	f(c.client)
+	return recorder, nil
+}
+
+// call calls the core library method with the client bound to the context.
+// The error of a rejected request is returned as APIError wrapping the error
+// of the core library.
+func (c *ngenixClient) call(ctx context.Context, method string, f func(client *restapi.Client) error) error {
+	var err error
+	recorder, beginErr := c.with(ctx, method, func(client *restapi.Client) {
+		err = f(client)
+	})
+	if beginErr != nil {
+		return beginErr
+	}
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return contextError(ctx, err)
+	}
+	if recorder.last != nil {
+		apiErr := *recorder.last
+		apiErr.err = err
+		return &apiErr
+	}
+	return err
+}
+
+// contextError returns the error of the interrupted operation instead of the
+// error of the request, which the core library does not wrap.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if err == nil {
+			return fmt.Errorf("request to Ngenix API was interrupted: %w", ctxErr)
+		}
+		return fmt.Errorf("request to Ngenix API was interrupted: %w, request error: %s", ctxErr, err.Error())
+	}
+	return err
+}
+
+// NewClient creates the core library client, its own requests, e.g. the
+// authentication, are sent as the requests of the other calls.
+func (c *ngenixClient) NewClient(ctx context.Context, host, username, password string) error {
+	return c.call(ctx, "NewClient", func(*restapi.Client) (err error) {
+		c.client, err = restapi.NewClient(host, username, password)
+		return err
+	})
+}
+
+// CustomerId returns the customer ID of the authenticated user, it makes no requests.
+func (c *ngenixClient) CustomerId() int {
+	return c.client.CustomerId()
+}
+
+// DnsZoneExist reports whether the DNS zone with the name exists. The core
+// library reports a failed request as a missing zone, the error is only
+// returned if the operation was interrupted.
+func (c *ngenixClient) DnsZoneExist(ctx context.Context, name string) (bool, error) {
+	var exist bool
+	if _, err := c.with(ctx, "DnsZoneExist", func(client *restapi.Client) {
+		exist = client.DnsZoneExist(name)
+	}); err != nil {
+		return false, err
+	}
+	if ctx.Err() != nil {
+		return false, contextError(ctx, nil)
+	}
+	return exist, nil
+}
+
+func (c *ngenixClient) GetDnsZoneById(ctx context.Context, id int) (*restapi.DnsZone, error) {
+	var dnsZone *restapi.DnsZone
+	err := c.call(ctx, "GetDnsZoneById", func(client *restapi.Client) (err error) {
+		dnsZone, err = client.GetDnsZoneById(id)
+		return err
+	})
+	return dnsZone, err
+}
+
+// GetAllDnsZonesList returns the DNS zones of the customer. The core library
+// returns an empty list if the request fails, so an interrupted operation is
+// reported as the error instead of no zones.
+func (c *ngenixClient) GetAllDnsZonesList(ctx context.Context) ([]restapi.DnsZone, error) {
+	var dnsZones []restapi.DnsZone
+	if _, err := c.with(ctx, "GetAllDnsZonesList", func(client *restapi.Client) {
+		dnsZones = client.GetAllDnsZonesList()
+	}); err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	return dnsZones, nil
+}
+
+func (c *ngenixClient) CreateDnsZone(ctx context.Context, dnsZone restapi.DnsZone) (*restapi.DnsZone, error) {
+	var created *restapi.DnsZone
+	err := c.call(ctx, "CreateDnsZone", func(client *restapi.Client) (err error) {
+		created, err = client.CreateDnsZone(dnsZone)
+		return err
+	})
+	return created, err
+}
+
+func (c *ngenixClient) UpdateDnsZone(ctx context.Context, id int, dnsZone restapi.DnsZone) (*restapi.DnsZone, error) {
+	var updated *restapi.DnsZone
+	err := c.call(ctx, "UpdateDnsZone", func(client *restapi.Client) (err error) {
+		updated, err = client.UpdateDnsZone(id, dnsZone)
+		return err
+	})
+	return updated, err
+}
+
+func (c *ngenixClient) DeleteDnsZone(ctx context.Context, id int) error {
+	return c.call(ctx, "DeleteDnsZone", func(client *restapi.Client) error {
+		return client.DeleteDnsZone(id)
+	})
+}
+
+func (c *ngenixClient) GetTrafficPatternById(ctx context.Context, id int) (*restapi.TrafficPattern, error) {
+	var trafficPattern *restapi.TrafficPattern
+	err := c.call(ctx, "GetTrafficPatternById", func(client *restapi.Client) (err error) {
+		trafficPattern, err = client.GetTrafficPatternById(id)
+		return err
+	})
+	return trafficPattern, err
+}
+
+// GetAllTrafficPatternsList returns the Traffic Patterns of the customer, an
+// interrupted operation is reported as the error instead of no Traffic Patterns.
+func (c *ngenixClient) GetAllTrafficPatternsList(ctx context.Context) ([]restapi.TrafficPattern, error) {
+	var trafficPatterns []restapi.TrafficPattern
+	if _, err := c.with(ctx, "GetAllTrafficPatternsList", func(client *restapi.Client) {
+		trafficPatterns = client.GetAllTrafficPatternsList()
+	}); err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	return trafficPatterns, nil
+}
+
+func (c *ngenixClient) CreateNewTrafficPatternForCustomer(ctx context.Context, trafficPattern restapi.TrafficPattern, customerId int) (*restapi.TrafficPattern, error) {
+	var created *restapi.TrafficPattern
+	err := c.call(ctx, "CreateNewTrafficPatternForCustomer", func(client *restapi.Client) (err error) {
+		created, err = client.CreateNewTrafficPatternForCustomer(trafficPattern, customerId)
+		return err
+	})
+	return created, err
+}
+
+func (c *ngenixClient) UpdateTrafficPatternById(ctx context.Context, trafficPattern restapi.TrafficPattern, id int) (*restapi.TrafficPattern, error) {
+	var updated *restapi.TrafficPattern
+	err := c.call(ctx, "UpdateTrafficPatternById", func(client *restapi.Client) (err error) {
+		updated, err = client.UpdateTrafficPatternById(trafficPattern, id)
+		return err
+	})
+	return updated, err
+}
+
+func (c *ngenixClient) DeleteTrafficPatternById(ctx context.Context, id int) error {
+	return c.call(ctx, "DeleteTrafficPatternById", func(client *restapi.Client) error {
+		return client.DeleteTrafficPatternById(id)
+	})
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// contextLoggingTransport logs every request with its context instead of sending it.
+type contextLoggingTransport struct{}
+
+func (contextLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tflog.Debug(req.Context(), "Sending Ngenix API request")
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestNgenixClientWith(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = tflog.SetField(ctx, "tf_resource_type", "ngenix_dnszone")
+
+	client := newNgenixClient(
+		&restapi.Client{},
+		contextLoggingTransport{},
+		map[string]any{"ngenix_host": "https://api.ngenix.test/"},
+	)
+
+	// Requests created without a context, as by the core library, are logged with the fields of the operation.
+	if err := getDnsZone(ctx, client); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unexpected error decoding the logs: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got: %v", entries)
+	}
+	for key, value := range map[string]any{
+		"tf_resource_type":     "ngenix_dnszone",
+		"ngenix_host":          "https://api.ngenix.test/",
+		"ngenix_client_method": "GetDnsZoneById",
+	} {
+		if entries[0][key] != value {
+			t.Errorf("expected the log field %s = %v, got: %v", key, value, entries[0][key])
+		}
+	}
+
+	// Requests of the canceled operation are not sent.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := getDnsZone(canceled, client); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the operation to be canceled, got: %v", err)
+	}
+}
+
+func TestContextError(t *testing.T) {
+	requestErr := errors.New("connection reset by peer")
+
+	if err := contextError(context.Background(), requestErr); err != requestErr {
+		t.Errorf("expected the request error to be returned as is, got: %v", err)
+	}
+	if err := contextError(context.Background(), nil); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := contextError(ctx, requestErr); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the operation canceled error, got: %v", err)
+	}
+	// The list methods of the core library report no errors, the interrupted operation is one.
+	if err := contextError(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the operation canceled error, got: %v", err)
+	}
+}
+
+// getDnsZone sends a request of a GetDnsZoneById call by the default
+// http.Client, as the core library does.
+func getDnsZone(ctx context.Context, client *ngenixClient) error {
+	return client.call(ctx, "GetDnsZoneById", func(*restapi.Client) error {
+		resp, err := http.Get("https://api.ngenix.test/dns-zone/1")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+}
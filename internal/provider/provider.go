@@ -5,15 +5,18 @@ package provider
 
 import (
 	"context"
-	"os"
-
-	"ngenix/restapi"
+	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -42,9 +45,15 @@ type ngenixProvider struct {
 
 // ngenixProviderModel maps provider schema data to a Go type.
 type ngenixProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
+	Host            types.String `tfsdk:"host"`
+	Username        types.String `tfsdk:"username"`
+	Password        types.String `tfsdk:"password"`
+	Profile         types.String `tfsdk:"profile"`
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+	MaxRetries      types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin    types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax    types.String `tfsdk:"retry_wait_max"`
+	RequestTimeout  types.String `tfsdk:"request_timeout"`
 }
 
 // Metadata returns the provider type name.
@@ -70,6 +79,46 @@ func (p *ngenixProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Sensitive:   true,
 				Description: "User token for Ngenix API. May also be provided via NGENIX_PASSWORD environment variable.",
 			},
+			"profile": schema.StringAttribute{
+				Optional: true,
+				Description: "Name of the credentials file profile providing host, username and password. " +
+					"May also be provided via NGENIX_PROFILE environment variable. The provider attributes take precedence over the profile, " +
+					"the profile takes precedence over the NGENIX_HOST, NGENIX_USERNAME and NGENIX_PASSWORD environment variables. " +
+					"If no profile is selected, the default profile is used for the values set neither by the attributes nor by the environment variables.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"credentials_file": schema.StringAttribute{
+				Optional: true,
+				Description: "Path of the INI or YAML credentials file with the profiles. " +
+					"May also be provided via NGENIX_CREDENTIALS_FILE environment variable. Defaults to ~/.ngenix/credentials.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				Description: "Maximum number of retries of a failed Ngenix API request, 0 disables retries. Defaults to 3. " +
+					"Rate limited requests (HTTP 429) are retried for any method, connection errors and HTTP 500, 502, 503 " +
+					"and 504 responses are retried only for idempotent requests, e.g. reads and deletes.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:    true,
+				Description: "Wait before the first retry as a duration, e.g. 500ms, doubled for every next retry. Defaults to 1s.",
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional: true,
+				Description: "Timeout of a single Ngenix API request as a duration, e.g. 30s, each retry gets its own timeout. " +
+					"0s disables the timeout. Defaults to 1m.",
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum wait between retries as a duration, e.g. 1m, the Retry-After response header is honored up to it. Defaults to 30s.",
+			},
 		},
 	}
 }
@@ -118,23 +167,16 @@ func (p *ngenixProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
-	host := os.Getenv("NGENIX_HOST")
-	username := os.Getenv("NGENIX_USERNAME")
-	password := os.Getenv("NGENIX_PASSWORD")
-
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
-	}
-
-	if !config.Username.IsNull() {
-		username = config.Username.ValueString()
-	}
-
-	if !config.Password.IsNull() {
-		password = config.Password.ValueString()
+	// Default values to the credentials file profile and environment
+	// variables, but override with Terraform configuration value if set.
+	credentials, credentialsDiags := credentialsFromConfig(config)
+	resp.Diagnostics.Append(credentialsDiags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	host := credentials.Host
+	username := credentials.Username
+	password := credentials.Password
 
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
@@ -143,7 +185,7 @@ func (p *ngenixProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			path.Root("host"),
 			"Missing Ngenix API Host",
 			"The provider cannot create the Ngenix API client as there is a missing or empty value for the Ngenix API host. "+
-				"Set the host value in the configuration or use the NGENIX_HOST environment variable. "+
+				"Set the host value in the configuration, use the NGENIX_HOST environment variable or a profile of the credentials file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -153,7 +195,7 @@ func (p *ngenixProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			path.Root("username"),
 			"Missing Ngenix API Username",
 			"The provider cannot create the Ngenix API client as there is a missing or empty value for the Ngenix API username. "+
-				"Set the username value in the configuration or use the NGENIX_USERNAME environment variable. "+
+				"Set the username value in the configuration, use the NGENIX_USERNAME environment variable or a profile of the credentials file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -163,11 +205,16 @@ func (p *ngenixProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			path.Root("password"),
 			"Missing Ngenix API Password",
 			"The provider cannot create the Ngenix API client as there is a missing or empty value for the Ngenix API password. "+
-				"Set the password value in the configuration or use the NGENIX_PASSWORD environment variable. "+
+				"Set the password value in the configuration, use the NGENIX_PASSWORD environment variable or a profile of the credentials file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	retry, retryDiags := retryPolicyFromConfig(config)
+	resp.Diagnostics.Append(retryDiags...)
+	requestTimeout, timeoutDiags := requestTimeoutFromConfig(config)
+	resp.Diagnostics.Append(timeoutDiags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -179,9 +226,22 @@ func (p *ngenixProvider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	tflog.Debug(ctx, "Creating Ngenix client")
 
+	// Failed requests are retried by the transport of the client, every
+	// attempt is logged to the ngenix_http subsystem. The transport is set
+	// up first, so it also sends the requests of restapi.NewClient.
+	transport := newRetryTransport(
+		newLoggingTransport(&timeoutTransport{next: installCoreLibraryTransport(), timeout: requestTimeout}, password),
+		retry,
+	)
+	// The requests of the resources and data sources are logged with the
+	// host and username of the provider.
+	ngenix := newNgenixClient(nil, transport, map[string]any{
+		"ngenix_host":     host,
+		"ngenix_username": username,
+	})
+
 	// Create a new Ngenix client using the configuration values.
-	client, err := restapi.NewClient(host, username, password)
-	if err != nil {
+	if err := ngenix.NewClient(ctx, host, username, password); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Ngenix API Client",
 			"An unexpected error occurred when creating the Ngenix API client. "+
@@ -193,17 +253,96 @@ func (p *ngenixProvider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	// Make the Ngenix client available during DataSource and Resource
 	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = ngenix
+	resp.ResourceData = ngenix
 
 	tflog.Info(ctx, "Configured Ngenix client", map[string]any{"success": true})
 }
 
+// retryPolicyFromConfig returns the retry policy of the provider configuration,
+// defaultRetryPolicy values are used for the attributes which are not set.
+func retryPolicyFromConfig(config ngenixProviderModel) (retryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	retry := defaultRetryPolicy
+	if config.MaxRetries.IsUnknown() || config.RetryWaitMin.IsUnknown() || config.RetryWaitMax.IsUnknown() {
+		diags.AddError(
+			"Unknown Ngenix API Retry Policy",
+			"The provider cannot create the Ngenix API client as there is an unknown configuration value for max_retries, retry_wait_min or retry_wait_max. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+		return retry, diags
+	}
+
+	if !config.MaxRetries.IsNull() {
+		retry.maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+	for _, wait := range []struct {
+		attribute string
+		value     types.String
+		duration  *time.Duration
+	}{
+		{"retry_wait_min", config.RetryWaitMin, &retry.waitMin},
+		{"retry_wait_max", config.RetryWaitMax, &retry.waitMax},
+	} {
+		if wait.value.IsNull() {
+			continue
+		}
+		duration, err := time.ParseDuration(wait.value.ValueString())
+		if err != nil || duration <= 0 {
+			diags.AddAttributeError(
+				path.Root(wait.attribute),
+				"Invalid Ngenix API Retry Wait",
+				fmt.Sprintf("%s should be a positive duration, e.g. 1s or 500ms, got: %q", wait.attribute, wait.value.ValueString()),
+			)
+			continue
+		}
+		*wait.duration = duration
+	}
+	if !diags.HasError() && retry.waitMin > retry.waitMax {
+		diags.AddAttributeError(
+			path.Root("retry_wait_min"),
+			"Invalid Ngenix API Retry Wait",
+			fmt.Sprintf("retry_wait_min %s should not be greater than retry_wait_max %s", retry.waitMin, retry.waitMax),
+		)
+	}
+	return retry, diags
+}
+
+// requestTimeoutFromConfig returns the request timeout of the provider configuration,
+// defaultRequestTimeout is used if it is not set.
+func requestTimeoutFromConfig(config ngenixProviderModel) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if config.RequestTimeout.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("request_timeout"),
+			"Unknown Ngenix API Request Timeout",
+			"The provider cannot create the Ngenix API client as there is an unknown configuration value for request_timeout. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+		return 0, diags
+	}
+	if config.RequestTimeout.IsNull() {
+		return defaultRequestTimeout, diags
+	}
+	timeout, err := time.ParseDuration(config.RequestTimeout.ValueString())
+	if err != nil || timeout < 0 {
+		diags.AddAttributeError(
+			path.Root("request_timeout"),
+			"Invalid Ngenix API Request Timeout",
+			fmt.Sprintf("request_timeout should be a duration, e.g. 30s, or 0s to disable the timeout, got: %q", config.RequestTimeout.ValueString()),
+		)
+	}
+	return timeout, diags
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *ngenixProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		DnsZoneDataSource,
+		DnsZoneSingleDataSource,
+		DnsZoneFileDataSource,
 		TrafficPatternDataSource,
+		TrafficPatternSingleDataSource,
 	}
 }
 
@@ -211,6 +350,8 @@ func (p *ngenixProvider) DataSources(_ context.Context) []func() datasource.Data
 func (p *ngenixProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		DnsZoneResource,
+		DnsRecordResource,
 		TrafficPatternResource,
+		TrafficPatternEntryResource,
 	}
 }
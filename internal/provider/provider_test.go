@@ -6,31 +6,58 @@ package provider
 import (
 	"fmt"
 	"os"
+	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
-var (
-	email = os.Getenv("EMAIL")
-	token = os.Getenv("TOKEN")
-)
+// testAccAPI is the in-memory Ngenix API shared by all acceptance tests.
+var testAccAPI = newFakeNgenixAPI()
+
+// testAccRealAPI is set by NGENIX_ACC_REAL_API=1 to run the acceptance tests
+// against the real Ngenix API configured by the NGENIX_HOST, NGENIX_USERNAME
+// and NGENIX_PASSWORD environment variables instead of the fake.
+var testAccRealAPI = os.Getenv("NGENIX_ACC_REAL_API") == "1"
 
 const (
 	// providerConfig is a shared configuration to combine with the actual
 	// test configuration so the Ngenix client is properly configured.
-	// It is also possible to use the NGENIX_ environment variables instead,
-	// such as updating the Makefile and running the testing through that tool.
+	// The host points to the fake Ngenix API started for the test run,
+	// so no real credentials are needed.
 	providerConfigString = `
 provider "ngenix" {
-  host     = "https://api.ngenix.net/api/v3/"
+  host     = "%s"
   username = "%s"
   password = "%s"
 }
 `
 )
 
-var providerConfig = fmt.Sprintf(providerConfigString, email, token)
+var providerConfig = testAccProviderConfig()
+
+// testAccProviderConfig returns the provider configuration of the fake Ngenix
+// API, the real one is configured by the environment variables.
+func testAccProviderConfig() string {
+	if testAccRealAPI {
+		return `
+provider "ngenix" {}
+`
+	}
+	return fmt.Sprintf(providerConfigString, testAccAPI.URL(), fakeUsername, fakePassword)
+}
+
+// testAccFakeAPIOnly skips the test relying on the objects seeded in the fake
+// Ngenix API or on changing its state, when the acceptance tests run against
+// the real API.
+func testAccFakeAPIOnly(t *testing.T) {
+	t.Helper()
+	if testAccRealAPI {
+		t.Skip("the test relies on the objects seeded in the fake Ngenix API")
+	}
+}
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
@@ -39,3 +66,87 @@ var providerConfig = fmt.Sprintf(providerConfigString, email, token)
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"ngenix": providerserver.NewProtocol6WithError(New("test")()),
 }
+
+func TestMain(m *testing.M) {
+	if testAccRealAPI && (os.Getenv("NGENIX_HOST") == "" || os.Getenv("NGENIX_USERNAME") == "" || os.Getenv("NGENIX_PASSWORD") == "") {
+		fmt.Fprintln(os.Stderr, "NGENIX_HOST, NGENIX_USERNAME and NGENIX_PASSWORD must be set to run the acceptance tests against the real Ngenix API")
+		os.Exit(1)
+	}
+	code := m.Run()
+	testAccAPI.Close()
+	os.Exit(code)
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	tests := map[string]struct {
+		config ngenixProviderModel
+		policy retryPolicy
+		err    bool
+	}{
+		"defaults": {
+			config: ngenixProviderModel{},
+			policy: defaultRetryPolicy,
+		},
+		"configured": {
+			config: ngenixProviderModel{
+				MaxRetries:   types.Int64Value(0),
+				RetryWaitMin: types.StringValue("250ms"),
+				RetryWaitMax: types.StringValue("1m"),
+			},
+			policy: retryPolicy{maxRetries: 0, waitMin: 250 * time.Millisecond, waitMax: time.Minute},
+		},
+		"invalid duration": {
+			config: ngenixProviderModel{RetryWaitMin: types.StringValue("soon")},
+			err:    true,
+		},
+		"negative duration": {
+			config: ngenixProviderModel{RetryWaitMax: types.StringValue("-1s")},
+			err:    true,
+		},
+		"min greater than max": {
+			config: ngenixProviderModel{RetryWaitMin: types.StringValue("1m"), RetryWaitMax: types.StringValue("10s")},
+			err:    true,
+		},
+		"unknown": {
+			config: ngenixProviderModel{MaxRetries: types.Int64Unknown()},
+			err:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, diags := retryPolicyFromConfig(test.config)
+			if diags.HasError() != test.err {
+				t.Fatalf("expected error %t, got: %v", test.err, diags)
+			}
+			if !test.err && policy != test.policy {
+				t.Errorf("expected %+v, got %+v", test.policy, policy)
+			}
+		})
+	}
+}
+
+func TestRequestTimeoutFromConfig(t *testing.T) {
+	tests := map[string]struct {
+		value   types.String
+		timeout time.Duration
+		err     bool
+	}{
+		"default":  {value: types.StringNull(), timeout: defaultRequestTimeout},
+		"set":      {value: types.StringValue("30s"), timeout: 30 * time.Second},
+		"disabled": {value: types.StringValue("0s"), timeout: 0},
+		"negative": {value: types.StringValue("-1s"), err: true},
+		"invalid":  {value: types.StringValue("soon"), err: true},
+		"unknown":  {value: types.StringUnknown(), err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			timeout, diags := requestTimeoutFromConfig(ngenixProviderModel{RequestTimeout: test.value})
+			if diags.HasError() != test.err {
+				t.Fatalf("expected error %t, got: %v", test.err, diags)
+			}
+			if !test.err && timeout != test.timeout {
+				t.Errorf("expected %s, got %s", test.timeout, timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// retryPolicy configures the retries of the failed Ngenix API requests.
+type retryPolicy struct {
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+}
+
+// defaultRetryPolicy is used for the provider attributes which are not set.
+var defaultRetryPolicy = retryPolicy{
+	maxRetries: 3,
+	waitMin:    time.Second,
+	waitMax:    30 * time.Second,
+}
+
+// idempotentMethods lists the HTTP methods which could be safely sent again
+// even if the previous request could have been processed by Ngenix.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// retryTransport is the http.RoundTripper of the Ngenix API client retrying
+// the failed requests with exponential backoff and jitter:
+//   - rate limited requests (429) are retried for any method, since they were
+//     rejected without being processed;
+//   - connection errors and 500, 502, 503 and 504 responses are retried only
+//     for idempotent methods and requests with an Idempotency-Key header,
+//     so e.g. a traffic pattern is never created twice.
+//
+// The Retry-After header of the retried responses is honored up to the maximum wait.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy retryPolicy
+
+	// sleep waits between the attempts, it is replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+	// jitter returns a random duration in [0, d), it is replaced in tests.
+	jitter func(d time.Duration) time.Duration
+}
+
+// newRetryTransport wraps the next transport, http.DefaultTransport is used if it is nil.
+func newRetryTransport(next http.RoundTripper, policy retryPolicy) *retryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{
+		next:   next,
+		policy: policy,
+		sleep:  sleepContext,
+		jitter: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return 0
+			}
+			return time.Duration(rand.Int63n(int64(d)))
+		},
+	}
+}
+
+// RoundTrip sends the request and retries it according to the retry policy.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.policy.maxRetries || !t.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = min(retryAfter, t.policy.waitMax)
+			}
+			// The body is drained, so the connection could be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			_ = resp.Body.Close()
+		}
+
+		fields := map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status_code"] = resp.StatusCode
+		}
+		tflog.Warn(ctx, "Retrying Ngenix API request", fields)
+
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether the failed request could be sent again.
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body is already consumed and could not be sent again.
+		return false
+	}
+	idempotent := idempotentMethods[req.Method] || req.Header.Get("Idempotency-Key") != ""
+	if err != nil {
+		// Requests are not retried once the resource operation is canceled or
+		// timed out, timed out attempts are retried as the connection errors.
+		if req.Context().Err() != nil {
+			return false
+		}
+		return idempotent
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// backoff returns the wait before the retry of the attempt, the wait is
+// doubled for every attempt up to the maximum, and the jitter spreads the
+// retries of the concurrent requests.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	wait := t.policy.waitMin
+	for i := 0; i < attempt && wait < t.policy.waitMax; i++ {
+		wait *= 2
+	}
+	wait = min(wait, t.policy.waitMax)
+	return wait/2 + t.jitter(wait/2)
+}
+
+// rewindRequest returns the request to send for the attempt with a fresh body.
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+	return attemptReq, nil
+}
+
+// parseRetryAfter parses the Retry-After header, either delay seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for the duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
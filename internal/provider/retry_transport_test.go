@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer is a stand-in for the Ngenix API failing the first requests.
+type flakyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures []func(w http.ResponseWriter)
+	bodies   []string
+}
+
+// newFlakyServer starts a server failing the first requests with the failures
+// and responding with 200 OK afterwards.
+func newFlakyServer(t *testing.T, failures ...func(w http.ResponseWriter)) *flakyServer {
+	s := &flakyServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		var failure func(w http.ResponseWriter)
+		if len(s.failures) > 0 {
+			failure, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if failure != nil {
+			failure(w)
+			return
+		}
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// attempts returns the number of requests received by the server.
+func (s *flakyServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+func respondWith(status int, header ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"code":"failure"}`)
+	}
+}
+
+// resetConnection closes the connection without a response.
+func resetConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic("connection could not be hijacked")
+	}
+	if conn, _, err := hijacker.Hijack(); err == nil {
+		_ = conn.Close()
+	}
+}
+
+// newTestRetryTransport returns the retry transport recording the waits instead of sleeping.
+func newTestRetryTransport(policy retryPolicy) (*retryTransport, *[]time.Duration) {
+	waits := []time.Duration{}
+	transport := newRetryTransport(nil, policy)
+	transport.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	transport.jitter = func(d time.Duration) time.Duration { return d }
+	return transport, &waits
+}
+
+func TestRetryTransport(t *testing.T) {
+	policy := retryPolicy{maxRetries: 3, waitMin: time.Second, waitMax: 3 * time.Second}
+
+	tests := map[string]struct {
+		method   string
+		header   map[string]string
+		failures []func(w http.ResponseWriter)
+		status   int
+		attempts int
+		waits    []time.Duration
+	}{
+		"success": {
+			method:   http.MethodGet,
+			status:   http.StatusOK,
+			attempts: 1,
+			waits:    []time.Duration{},
+		},
+		"get retried on server errors": {
+			method:   http.MethodGet,
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusServiceUnavailable), respondWith(http.StatusBadGateway)},
+			status:   http.StatusOK,
+			attempts: 3,
+			waits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		"get retried on connection reset": {
+			method:   http.MethodGet,
+			failures: []func(w http.ResponseWriter){resetConnection},
+			status:   http.StatusOK,
+			attempts: 2,
+			waits:    []time.Duration{time.Second},
+		},
+		"delete retried up to max retries": {
+			method: http.MethodDelete,
+			failures: []func(w http.ResponseWriter){
+				respondWith(http.StatusInternalServerError), respondWith(http.StatusInternalServerError),
+				respondWith(http.StatusInternalServerError), respondWith(http.StatusInternalServerError),
+			},
+			status:   http.StatusInternalServerError,
+			attempts: 4,
+			waits:    []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		"post not retried on server errors": {
+			method:   http.MethodPost,
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusServiceUnavailable)},
+			status:   http.StatusServiceUnavailable,
+			attempts: 1,
+			waits:    []time.Duration{},
+		},
+		"post with idempotency key retried on server errors": {
+			method:   http.MethodPost,
+			header:   map[string]string{"Idempotency-Key": "create-1"},
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusServiceUnavailable)},
+			status:   http.StatusOK,
+			attempts: 2,
+			waits:    []time.Duration{time.Second},
+		},
+		"patch retried when rate limited": {
+			method:   http.MethodPatch,
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusTooManyRequests, "Retry-After", "2")},
+			status:   http.StatusOK,
+			attempts: 2,
+			waits:    []time.Duration{2 * time.Second},
+		},
+		"retry after capped by max wait": {
+			method:   http.MethodGet,
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusTooManyRequests, "Retry-After", "3600")},
+			status:   http.StatusOK,
+			attempts: 2,
+			waits:    []time.Duration{3 * time.Second},
+		},
+		"client errors not retried": {
+			method:   http.MethodGet,
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusNotFound)},
+			status:   http.StatusNotFound,
+			attempts: 1,
+			waits:    []time.Duration{},
+		},
+		"not implemented not retried": {
+			method:   http.MethodGet,
+			failures: []func(w http.ResponseWriter){respondWith(http.StatusNotImplemented)},
+			status:   http.StatusNotImplemented,
+			attempts: 1,
+			waits:    []time.Duration{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newFlakyServer(t, test.failures...)
+			transport, waits := newTestRetryTransport(policy)
+
+			req, _ := http.NewRequest(test.method, server.URL, strings.NewReader(`{"name":"test"}`))
+			for key, value := range test.header {
+				req.Header.Set(key, value)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Errorf("expected status %d, got %d", test.status, resp.StatusCode)
+			}
+			if attempts := server.attempts(); attempts != test.attempts {
+				t.Errorf("expected %d attempts, got %d", test.attempts, attempts)
+			}
+			if len(*waits) != len(test.waits) {
+				t.Fatalf("expected waits %v, got %v", test.waits, *waits)
+			}
+			for i := range test.waits {
+				if (*waits)[i] != test.waits[i] {
+					t.Errorf("expected waits %v, got %v", test.waits, *waits)
+					break
+				}
+			}
+			// The body is sent again with every retry.
+			for _, body := range server.bodies {
+				if body != `{"name":"test"}` {
+					t.Errorf("expected the request body to be sent again, got %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportPostConnectionReset(t *testing.T) {
+	server := newFlakyServer(t, resetConnection)
+	transport, _ := newTestRetryTransport(defaultRetryPolicy)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"name":"test"}`))
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("expected the connection error")
+	}
+	if attempts := server.attempts(); attempts != 1 {
+		t.Errorf("expected the post request not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryTransportContextCanceled(t *testing.T) {
+	server := newFlakyServer(t, respondWith(http.StatusServiceUnavailable), respondWith(http.StatusServiceUnavailable))
+	transport := newRetryTransport(nil, retryPolicy{maxRetries: 3, waitMin: time.Hour, waitMax: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error while waiting, got: %v", err)
+	}
+	if attempts := server.attempts(); attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryTransportBackoffJitter(t *testing.T) {
+	transport := newRetryTransport(nil, retryPolicy{maxRetries: 10, waitMin: time.Second, waitMax: 10 * time.Second})
+	for attempt, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		for i := 0; i < 20; i++ {
+			if backoff := transport.backoff(attempt); backoff < wait/2 || backoff >= wait {
+				t.Fatalf("attempt %d: expected the backoff in [%s, %s), got %s", attempt, wait/2, wait, backoff)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		value string
+		wait  time.Duration
+		ok    bool
+	}{
+		"empty":       {value: "", ok: false},
+		"seconds":     {value: "120", wait: 2 * time.Minute, ok: true},
+		"http date":   {value: "Mon, 01 Jan 2024 12:00:30 GMT", wait: 30 * time.Second, ok: true},
+		"past date":   {value: "Mon, 01 Jan 2024 11:00:00 GMT", wait: 0, ok: true},
+		"not a delay": {value: "soon", ok: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(test.value, now)
+			if ok != test.ok || wait != test.wait {
+				t.Errorf("expected %s, %t, got %s, %t", test.wait, test.ok, wait, ok)
+			}
+		})
+	}
+}
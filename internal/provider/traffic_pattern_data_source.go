@@ -3,11 +3,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"ngenix/restapi"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -24,15 +29,20 @@ func TrafficPatternDataSource() datasource.DataSource {
 
 // TrafficPatternDataSource is the data source implementation.
 type trafficPatternDataSource struct {
-	client *restapi.Client
+	client *ngenixClient
 }
 
 // TrafficPatternSourceModel maps schema data.
 type TrafficPatternSourceModel struct {
+	Name            types.String          `tfsdk:"name"`
+	NameRegex       types.String          `tfsdk:"name_regex"`
+	Type            types.String          `tfsdk:"type"`
+	ContentType     types.String          `tfsdk:"content_type"`
 	TrafficPatterns []TrafficPatternModel `tfsdk:"traffic_patterns"`
 }
 
 type TrafficPatternModel struct {
+	ID          types.String        `tfsdk:"id"`
 	Name        types.String        `tfsdk:"name"`
 	Type        types.String        `tfsdk:"type"`
 	ContentType types.String        `tfsdk:"content_type"`
@@ -65,11 +75,11 @@ func (d *trafficPatternDataSource) Configure(_ context.Context, req datasource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*restapi.Client)
+	client, ok := req.ProviderData.(*ngenixClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *ngenix.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -83,11 +93,41 @@ func (d *trafficPatternDataSource) Schema(_ context.Context, _ datasource.Schema
 	resp.Schema = schema.Schema{
 		Description: "Manages a Traffic Pattern.",
 		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only the Traffic Patterns with this exact name are returned.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("name_regex")),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only the Traffic Patterns with the name matching this regular expression (RE2 syntax) are returned.",
+			},
+			"type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only the Traffic Patterns of this type are returned.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(TrafficPatternTypes...),
+				},
+			},
+			"content_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only the Traffic Patterns of this content type are returned.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(TrafficPatternContentTypes...),
+				},
+			},
 			"traffic_patterns": schema.ListNestedAttribute{
-				Description: "List of Traffic Patterns.",
+				Description: "List of Traffic Patterns matching the filters.",
 				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern ID",
+						},
 						"name": schema.StringAttribute{
 							Required:    true,
 							Description: "Traffic pattern name",
@@ -157,29 +197,45 @@ func (d *trafficPatternDataSource) Schema(_ context.Context, _ datasource.Schema
 func (d *trafficPatternDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	// Get current state.
 	var state TrafficPatternSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	filter, err := newTrafficPatternFilter(state)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name_regex"),
+			"Invalid Traffic Pattern name regex",
+			fmt.Sprintf("Could not compile name_regex, error: %s", err.Error()),
+		)
+		return
+	}
+
 	// Getting all DNS zones for provided username.
-	trafficPatternsList := d.client.GetAllTrafficPatternsList()
+	trafficPatternsList, err := d.client.GetAllTrafficPatternsList(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Ngenix Traffic Patterns",
+			fmt.Sprintf("Could not read Ngenix Traffic Patterns, error: %s", err.Error()),
+		)
+		return
+	}
 	// Map response body to model.
+	state.TrafficPatterns = []TrafficPatternModel{}
 	for _, trafficPattern := range trafficPatternsList {
+		if !filter.match(trafficPattern) {
+			continue
+		}
 		tpState := TrafficPatternModel{
 			Name:        types.StringPointerValue(trafficPattern.Name),
 			Type:        types.StringPointerValue(trafficPattern.Type),
 			ContentType: types.StringPointerValue(trafficPattern.ContentType),
 		}
-		// Traffic patterns.
-		for _, pattern := range trafficPattern.Patterns {
-			tpState.Patterns = append(tpState.Patterns, PatternsDataModel{
-				Addr:          types.StringPointerValue(pattern.Addr),
-				CommonString:  types.StringPointerValue(pattern.CommonString),
-				CountryCode:   types.StringPointerValue(pattern.CountryCode),
-				HttpMethod:    types.StringPointerValue(pattern.HttpMethod),
-				Asn:           types.Int64PointerValue(pattern.Asn),
-				Md5HashString: types.StringPointerValue(pattern.Md5HashString),
-				Ttl:           types.Int64PointerValue(pattern.Ttl),
-				Expires:       types.Int64PointerValue(pattern.Expires),
-				Comment:       types.StringValue(pattern.Comment),
-			})
+		if trafficPattern.ID != nil {
+			tpState.ID = types.StringValue(strconv.Itoa(*trafficPattern.ID))
 		}
+		// Traffic patterns.
+		tpState.Patterns = patternsDataModels(trafficPattern.Patterns)
 		state.TrafficPatterns = append(state.TrafficPatterns, tpState)
 	}
 
@@ -190,3 +246,64 @@ func (d *trafficPatternDataSource) Read(ctx context.Context, req datasource.Read
 		return
 	}
 }
+
+// trafficPatternFilter holds the filters of the ngenix_traffic_patterns data source, empty filters match any value.
+type trafficPatternFilter struct {
+	name        string
+	nameRegex   *regexp.Regexp
+	tpType      string
+	contentType string
+}
+
+// newTrafficPatternFilter builds the filter from the data source configuration.
+func newTrafficPatternFilter(config TrafficPatternSourceModel) (*trafficPatternFilter, error) {
+	filter := &trafficPatternFilter{
+		name:        config.Name.ValueString(),
+		tpType:      config.Type.ValueString(),
+		contentType: config.ContentType.ValueString(),
+	}
+	if !config.NameRegex.IsNull() {
+		nameRegex, err := regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		filter.nameRegex = nameRegex
+	}
+	return filter, nil
+}
+
+// match reports whether the Traffic Pattern matches all the filters.
+func (f *trafficPatternFilter) match(trafficPattern restapi.TrafficPattern) bool {
+	name := stringPointerValue(trafficPattern.Name)
+	return (f.name == "" || name == f.name) &&
+		(f.nameRegex == nil || f.nameRegex.MatchString(name)) &&
+		(f.tpType == "" || stringPointerValue(trafficPattern.Type) == f.tpType) &&
+		(f.contentType == "" || stringPointerValue(trafficPattern.ContentType) == f.contentType)
+}
+
+// stringPointerValue returns the string the pointer points to, empty for nil.
+func stringPointerValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// patternsDataModels maps the patterns returned by Ngenix to the data source model.
+func patternsDataModels(patterns []*restapi.Patterns) []PatternsDataModel {
+	var models []PatternsDataModel
+	for _, pattern := range patterns {
+		models = append(models, PatternsDataModel{
+			Addr:          types.StringPointerValue(pattern.Addr),
+			CommonString:  types.StringPointerValue(pattern.CommonString),
+			CountryCode:   types.StringPointerValue(pattern.CountryCode),
+			HttpMethod:    types.StringPointerValue(pattern.HttpMethod),
+			Asn:           types.Int64PointerValue(pattern.Asn),
+			Md5HashString: types.StringPointerValue(pattern.Md5HashString),
+			Ttl:           types.Int64PointerValue(pattern.Ttl),
+			Expires:       types.Int64PointerValue(pattern.Expires),
+			Comment:       types.StringValue(pattern.Comment),
+		})
+	}
+	return models
+}
@@ -1,12 +1,17 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 func TestTrafficPatternDataSource(t *testing.T) {
+	testAccFakeAPIOnly(t)
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
@@ -14,10 +19,95 @@ func TestTrafficPatternDataSource(t *testing.T) {
 			{
 				Config: providerConfig + `data "ngenix_traffic_patterns" "example" {}`,
 				Check: resource.ComposeAggregateTestCheckFunc(
-					// Verify number of traffic patterns returned.
-					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.#", "20"),
+					// Verify number of traffic patterns returned, patterns of other customers are not visible.
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.#", "2"),
+					// Verify the seeded traffic patterns.
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.0.name", "seed-commonlist"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.1.type", "blacklist"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.0.id", "11"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.1.id", "12"),
+				),
+			},
+			// Filters are applied before the state is set.
+			{
+				Config: providerConfig + `data "ngenix_traffic_patterns" "example" {
+  type = "blacklist"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.#", "1"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.0.name", "seed-blacklist"),
 				),
 			},
+			{
+				Config: providerConfig + `data "ngenix_traffic_patterns" "example" {
+  name_regex   = "^seed-"
+  content_type = "addr"
+}`,
+				Check: resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.#", "2"),
+			},
+			{
+				Config: providerConfig + `data "ngenix_traffic_patterns" "example" {
+  name = "seed-commonlist"
+  type = "blacklist"
+}`,
+				Check: resource.TestCheckResourceAttr("data.ngenix_traffic_patterns.example", "traffic_patterns.#", "0"),
+			},
+			{
+				Config: providerConfig + `data "ngenix_traffic_patterns" "example" {
+  name_regex = "["
+}`,
+				ExpectError: regexp.MustCompile("Invalid Traffic Pattern name regex"),
+			},
 		},
 	})
 }
+
+func TestTrafficPatternFilter(t *testing.T) {
+	name, tpType, contentType := "office-networks", "whitelist", "addr"
+	trafficPattern := restapi.TrafficPattern{Name: &name, Type: &tpType, ContentType: &contentType}
+
+	tests := map[string]struct {
+		config TrafficPatternSourceModel
+		match  bool
+	}{
+		"no filters": {
+			config: TrafficPatternSourceModel{},
+			match:  true,
+		},
+		"name": {
+			config: TrafficPatternSourceModel{Name: types.StringValue("office-networks")},
+			match:  true,
+		},
+		"other name": {
+			config: TrafficPatternSourceModel{Name: types.StringValue("office")},
+			match:  false,
+		},
+		"name regex": {
+			config: TrafficPatternSourceModel{NameRegex: types.StringValue("^office-")},
+			match:  true,
+		},
+		"type and content type": {
+			config: TrafficPatternSourceModel{Type: types.StringValue("whitelist"), ContentType: types.StringValue("addr")},
+			match:  true,
+		},
+		"other content type": {
+			config: TrafficPatternSourceModel{Type: types.StringValue("whitelist"), ContentType: types.StringValue("asn")},
+			match:  false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, err := newTrafficPatternFilter(test.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if match := filter.match(trafficPattern); match != test.match {
+				t.Errorf("expected match %t, got %t", test.match, match)
+			}
+		})
+	}
+
+	if _, err := newTrafficPatternFilter(TrafficPatternSourceModel{NameRegex: types.StringValue("[")}); err == nil {
+		t.Error("expected an error for an invalid name_regex")
+	}
+}
@@ -0,0 +1,41 @@
+package provider
+
+// diffPatterns returns the patterns to add to and to remove from the current
+// list to get the planned one. Duplicates are counted, a pattern with changed
+// ttl, expires or comment is removed and added again.
+func diffPatterns(current, planned []*PatternsModel) (added, removed []*PatternsModel) {
+	counts := map[string]int{}
+	for _, pattern := range current {
+		if pattern != nil {
+			counts[patternKey(pattern)]++
+		}
+	}
+	for _, pattern := range planned {
+		if pattern == nil {
+			continue
+		}
+		key := patternKey(pattern)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		added = append(added, pattern)
+	}
+	for _, pattern := range current {
+		if pattern == nil {
+			continue
+		}
+		key := patternKey(pattern)
+		if counts[key] > 0 {
+			counts[key]--
+			removed = append(removed, pattern)
+		}
+	}
+	return added, removed
+}
+
+// subtractPatterns returns the patterns without the removed ones, duplicates are counted.
+func subtractPatterns(patterns, removed []*PatternsModel) []*PatternsModel {
+	kept, _ := diffPatterns(removed, patterns)
+	return kept
+}
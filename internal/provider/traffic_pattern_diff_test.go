@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDiffPatterns(t *testing.T) {
+	pattern := func(addr, comment string) *PatternsModel {
+		return &PatternsModel{Addr: types.StringValue(addr), Comment: types.StringValue(comment)}
+	}
+	current := []*PatternsModel{
+		pattern("192.0.2.0/24", ""),
+		pattern("198.51.100.0/24", ""),
+		pattern("203.0.113.0/24", "old"),
+		pattern("203.0.113.0/24", "old"),
+	}
+	planned := []*PatternsModel{
+		pattern("198.51.100.0/24", ""),
+		pattern("203.0.113.0/24", "old"),
+		pattern("203.0.113.0/24", "new"),
+		pattern("2001:db8::/32", ""),
+	}
+
+	added, removed := diffPatterns(current, planned)
+	if expected := patternKeys([]*PatternsModel{planned[2], planned[3]}); !slices.Equal(patternKeys(added), expected) {
+		t.Errorf("unexpected added patterns: %v, expected: %v", patternKeys(added), expected)
+	}
+	if expected := patternKeys([]*PatternsModel{current[0], current[3]}); !slices.Equal(patternKeys(removed), expected) {
+		t.Errorf("unexpected removed patterns: %v, expected: %v", patternKeys(removed), expected)
+	}
+
+	// Nothing is sent for the same patterns in another order.
+	slices.Reverse(planned)
+	if added, removed := diffPatterns(planned, slices.Clone(planned)); len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no changes, got added: %v, removed: %v", added, removed)
+	}
+}
@@ -0,0 +1,656 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &trafficPatternEntryResource{}
+	_ resource.ResourceWithConfigure   = &trafficPatternEntryResource{}
+	_ resource.ResourceWithImportState = &trafficPatternEntryResource{}
+	_ resource.ResourceWithModifyPlan  = &trafficPatternEntryResource{}
+)
+
+// TrafficPatternEntryResource is a helper function to simplify the provider implementation.
+func TrafficPatternEntryResource() resource.Resource {
+	return &trafficPatternEntryResource{}
+}
+
+// trafficPatternEntryResource is the resource implementation.
+type trafficPatternEntryResource struct {
+	client *ngenixClient
+	// patterns reuses the traffic pattern transformations and validations.
+	patterns trafficPatternResource
+}
+
+// trafficPatternEntryResourceModel maps the resource schema data.
+type trafficPatternEntryResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	PatternID   types.String `tfsdk:"pattern_id"`
+	Value       types.String `tfsdk:"value"`
+	Ttl         types.Int64  `tfsdk:"ttl"`
+	Expires     types.Int64  `tfsdk:"expires"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+	Comment     types.String `tfsdk:"comment"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+	// Expiry of the entry with ttl or expires.
+	RecreateExpired types.Bool   `tfsdk:"recreate_expired"`
+	Expiration      types.String `tfsdk:"expiration"`
+	Expired         types.Bool   `tfsdk:"expired"`
+}
+
+// expiry returns the ttl and expires of the entry sent to Ngenix, with
+// expires_at converted to one of them.
+func (m *trafficPatternEntryResourceModel) expiry() (ttl, expires types.Int64, err error) {
+	if m.ExpiresAt.IsNull() || m.ExpiresAt.IsUnknown() {
+		return m.Ttl, m.Expires, nil
+	}
+	expires, ttl, err = parsePatternExpiresAt(m.ExpiresAt.ValueString())
+	return ttl, expires, err
+}
+
+// pattern returns the entry as a pattern of the content type.
+func (m *trafficPatternEntryResourceModel) pattern(contentType string) (*PatternsModel, error) {
+	ttl, expires, err := m.expiry()
+	if err != nil {
+		return nil, err
+	}
+	pattern := &PatternsModel{
+		Ttl:     ttl,
+		Expires: expires,
+		Comment: m.Comment,
+	}
+	valueAttribute, ok := PatternContentTypeAttributes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("content type %s is not supported", contentType)
+	}
+	if err := setPatternAttribute(pattern, valueAttribute, m.Value.ValueString()); err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// setPattern copies the pattern values read from the API to the model. The
+// expires_at is kept while Ngenix stores the ttl or expires it was sent as.
+func (m *trafficPatternEntryResourceModel) setPattern(pattern *PatternsModel) {
+	if ttl, expires, err := m.expiry(); err != nil || !ttl.Equal(pattern.Ttl) || !expires.Equal(pattern.Expires) {
+		m.ExpiresAt = types.StringNull()
+	}
+	if m.ExpiresAt.IsNull() {
+		m.Ttl = pattern.Ttl
+		m.Expires = pattern.Expires
+	}
+	m.Comment = pattern.Comment
+	m.ID = types.StringValue(m.PatternID.ValueString() + "/" + m.Value.ValueString())
+}
+
+// patternValue returns the value of the pattern for the content type.
+func patternValue(pattern *PatternsModel, contentType string) string {
+	switch contentType {
+	case "addr":
+		return pattern.Addr.ValueString()
+	case "commonString":
+		return pattern.CommonString.ValueString()
+	case "countryCode":
+		return pattern.CountryCode.ValueString()
+	case "httpMethod":
+		return pattern.HttpMethod.ValueString()
+	case "asn":
+		return strconv.FormatInt(pattern.Asn.ValueInt64(), 10)
+	case "md5HashString":
+		return pattern.Md5HashString.ValueString()
+	}
+	return ""
+}
+
+// trafficPatternEntryFieldPath maps the fields of the entry rejected by Ngenix
+// API to the resource attributes, the entry is sent at the index of the list.
+func trafficPatternEntryFieldPath(index int) func(field apiField) (path.Path, bool) {
+	return func(field apiField) (path.Path, bool) {
+		if field.name != "patterns" || index < 0 || field.index != index {
+			return path.Empty(), false
+		}
+		switch field.attribute {
+		case "ttl", "expires", "comment":
+			return path.Root(field.attribute), true
+		}
+		return path.Root("value"), true
+	}
+}
+
+// trafficPatternLocks serializes read-modify-write cycles of traffic pattern
+// lists, since entries of one list could be managed by several resources
+// applied in parallel.
+var trafficPatternLocks = struct {
+	sync.Mutex
+	patterns map[int]*sync.Mutex
+}{patterns: map[int]*sync.Mutex{}}
+
+// lockTrafficPattern locks the traffic pattern list and returns the unlock function.
+func lockTrafficPattern(tpId int) func() {
+	trafficPatternLocks.Lock()
+	patternLock, ok := trafficPatternLocks.patterns[tpId]
+	if !ok {
+		patternLock = &sync.Mutex{}
+		trafficPatternLocks.patterns[tpId] = patternLock
+	}
+	trafficPatternLocks.Unlock()
+
+	patternLock.Lock()
+	return patternLock.Unlock
+}
+
+// trafficPatternID parses the traffic pattern ID.
+func trafficPatternID(id types.String) (int, error) {
+	tpId, err := strconv.Atoi(id.ValueString())
+	if err != nil || tpId <= 0 {
+		return 0, fmt.Errorf("traffic pattern ID %q is not a valid numeric ID", id.ValueString())
+	}
+	return tpId, nil
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *trafficPatternEntryResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ngenixClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *trafficPatternEntryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_traffic_pattern_entry"
+}
+
+// Schema defines the schema for the resource.
+func (r *trafficPatternEntryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a single entry of an existing Traffic Pattern, other entries of the list are left untouched. " +
+			"An entry is identified by its value within the list. The list should not be managed by ngenix_traffic_pattern at the same time. " +
+			"Every change reads the list and writes it back whole, the changes of entries of one list are serialized within a single Terraform run only, " +
+			"so concurrent applies of separate configurations managing entries of the same list could overwrite each other.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Traffic pattern entry identifier in format pattern_id/value.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of the last Terraform update of the traffic pattern entry.",
+			},
+			"pattern_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Traffic pattern ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				Required: true,
+				Description: "Traffic pattern entry value according to the content type of the list, " +
+					"e.g. 192.0.2.0/24 for addr, GET for httpMethod or 13335 for asn.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Traffic pattern TTL",
+			},
+			"expires": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Traffic pattern expiration date",
+			},
+			"expires_at": schema.StringAttribute{
+				Optional: true,
+				Description: "Traffic pattern expiration as an RFC 3339 timestamp, e.g. 2025-12-31T23:59:59Z, sent as expires, " +
+					"or as a duration, e.g. 72h, sent as ttl. Conflicts with ttl and expires.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ttl"), path.MatchRoot("expires")),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Traffic pattern comment",
+				Default:     stringdefault.StaticString(""),
+			},
+			"recreate_expired": schema.BoolAttribute{
+				Description: "Add the entry removed by Ngenix after its ttl or expires lapsed again on the next apply. " +
+					"By default the expired entry is kept in the state and marked as expired, so it produces no diff.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"expiration": schema.StringAttribute{
+				Description: "Absolute expiration time of the entry with ttl or expires in RFC 3339 format. " +
+					"The expiration time of an entry with ttl is known only if the entry was added by Terraform.",
+				Computed: true,
+			},
+			"expired": schema.BoolAttribute{
+				Description: "Whether the entry was removed by Ngenix after its ttl or expires lapsed.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ModifyPlan validates the entry against the content type of the list, which
+// is known only from the API.
+func (r *trafficPatternEntryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan trafficPatternEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if _, _, err := plan.expiry(); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("expires_at"), "Invalid expires_at value", err.Error())
+		return
+	}
+	// The rest is validated against the list, once the provider is configured.
+	if r.client == nil || plan.PatternID.IsUnknown() || plan.Value.IsUnknown() || plan.Ttl.IsUnknown() || plan.Expires.IsUnknown() || plan.ExpiresAt.IsUnknown() {
+		return
+	}
+	tpId, err := trafficPatternID(plan.PatternID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pattern_id"), "Invalid Traffic Pattern ID", err.Error())
+		return
+	}
+
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+	if isNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("pattern_id"),
+			"Ngenix Traffic Pattern not found",
+			fmt.Sprintf("Traffic Pattern with ID = %d was not found in Ngenix.", tpId),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix Traffic Pattern",
+			fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+		)
+		return
+	}
+	r.validateEntry(&plan, trafficPattern, &resp.Diagnostics)
+}
+
+// validateEntry checks the entry against the type and the content type of the list.
+func (r *trafficPatternEntryResource) validateEntry(entry *trafficPatternEntryResourceModel, trafficPattern *restapi.TrafficPattern, diags *diag.Diagnostics) {
+	// Requirement: Types [filterlist, blacklist] are Read-Only.
+	if restapi.IsValueInRange(*trafficPattern.Type, TrafficPatternROTypes) {
+		diags.AddAttributeError(
+			path.Root("pattern_id"),
+			"Traffic pattern types [blacklist, filterlist] are Read-Only",
+			"Traffic pattern types [blacklist, filterlist] are Read-Only! It is not possible to manage them by Terraform",
+		)
+		return
+	}
+
+	contentType := *trafficPattern.ContentType
+	pattern, err := entry.pattern(contentType)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("value"),
+			"Error while Traffic Pattern validation process with Content type field",
+			fmt.Sprintf("Traffic Pattern is not valid, error: %s", err.Error()),
+		)
+		return
+	}
+	r.patterns.validatePatterns(contentType, []*PatternsModel{pattern}, types.BoolValue(false), func(_ int, attribute, summary, detail string) {
+		if attribute == "" {
+			diags.AddError(summary, detail)
+			return
+		}
+		diags.AddAttributeError(path.Root("value"), summary, detail)
+	})
+}
+
+// Create adds the entry to the traffic pattern and sets the initial Terraform state.
+func (r *trafficPatternEntryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan.
+	var plan trafficPatternEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tpId, err := trafficPatternID(plan.PatternID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Ngenix Traffic Pattern entry", err.Error())
+		return
+	}
+	unlock := lockTrafficPattern(tpId)
+	defer unlock()
+
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix Traffic Pattern",
+			fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+		)
+		return
+	}
+	r.validateEntry(&plan, trafficPattern, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Entries are identified by their values, so a value could not be added twice.
+	if _, ok := r.findEntry(trafficPattern, plan.Value.ValueString()); ok {
+		resp.Diagnostics.AddError(
+			"Ngenix Traffic Pattern entry already exists",
+			fmt.Sprintf("Traffic Pattern with ID = %d already contains %s, import it with ID %s/%s to manage it by Terraform.",
+				tpId, plan.Value.ValueString(), plan.PatternID.ValueString(), plan.Value.ValueString()),
+		)
+		return
+	}
+
+	pattern, diags := r.replaceEntry(ctx, tpId, trafficPattern, plan.Value.ValueString(), &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.setPattern(pattern)
+	plan.setExpiration(true, time.Now())
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	tflog.Trace(ctx, "Traffic pattern entry was created successfully!")
+
+	// Set state to fully populated data.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *trafficPatternEntryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state.
+	var state trafficPatternEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tpId, err := trafficPatternID(state.PatternID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Ngenix Traffic Pattern entry", err.Error())
+		return
+	}
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+	if isNotFound(err) {
+		resp.Diagnostics.AddWarning(
+			"Ngenix Traffic Pattern not found",
+			fmt.Sprintf("Traffic Pattern with ID = %d was not found in Ngenix, entry %s is removed from the state.", tpId, state.ID.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix Traffic Pattern",
+			fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+		)
+		return
+	}
+
+	// State stored before recreate_expired was introduced or imported has no value for it.
+	if state.RecreateExpired.IsNull() {
+		state.RecreateExpired = types.BoolValue(false)
+	}
+	pattern, ok := r.findEntry(trafficPattern, state.Value.ValueString())
+	if !ok && state.isExpired(time.Now()) {
+		// Entry was removed by Ngenix after its ttl or expires lapsed, it is kept
+		// in the state unless recreate_expired is set.
+		if !state.RecreateExpired.ValueBool() {
+			state.Expired = types.BoolValue(true)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Ngenix Traffic Pattern entry expired",
+			fmt.Sprintf("Traffic Pattern entry %s expired at %s and was removed by Ngenix, it will be re-created on the next apply.",
+				state.ID.ValueString(), state.Expiration.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if !ok {
+		// Entry was deleted outside of Terraform, let Terraform re-create it.
+		resp.Diagnostics.AddWarning(
+			"Ngenix Traffic Pattern entry not found",
+			fmt.Sprintf("Traffic Pattern entry %s was not found in Ngenix and is removed from the state, it will be re-created on the next apply.", state.ID.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.setPattern(pattern)
+	state.setExpiration(false, time.Now())
+
+	tflog.Trace(ctx, "Traffic pattern entry was read successfully!")
+
+	// Set refreshed state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update replaces the entry in the traffic pattern and sets the updated Terraform state on success.
+func (r *trafficPatternEntryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and state.
+	var plan, state trafficPatternEntryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tpId, err := trafficPatternID(plan.PatternID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Ngenix Traffic Pattern entry", err.Error())
+		return
+	}
+	unlock := lockTrafficPattern(tpId)
+	defer unlock()
+
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix Traffic Pattern",
+			fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+		)
+		return
+	}
+	r.validateEntry(&plan, trafficPattern, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The entry is replaced with the new ttl, expires and comment by a single request,
+	// so it is not lost if the request fails.
+	pattern, diags := r.replaceEntry(ctx, tpId, trafficPattern, state.Value.ValueString(), &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.setPattern(pattern)
+	plan.setExpiration(true, time.Now())
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	tflog.Trace(ctx, "Traffic pattern entry was updated successfully!")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the entry from the traffic pattern and the Terraform state on success.
+func (r *trafficPatternEntryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state.
+	var state trafficPatternEntryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tpId, err := trafficPatternID(state.PatternID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Ngenix Traffic Pattern entry", err.Error())
+		return
+	}
+	unlock := lockTrafficPattern(tpId)
+	defer unlock()
+
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+	if isNotFound(err) {
+		// Entries are gone together with the traffic pattern.
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Ngenix Traffic Pattern",
+			fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+		)
+		return
+	}
+	if _, ok := r.findEntry(trafficPattern, state.Value.ValueString()); !ok {
+		return
+	}
+	_, diags := r.replaceEntry(ctx, tpId, trafficPattern, state.Value.ValueString(), nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Traffic pattern entry was deleted successfully!")
+}
+
+// ImportState imports a traffic pattern entry by pattern_id/value.
+func (r *trafficPatternEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The value could contain slashes, e.g. 192.0.2.0/24.
+	patternId, value, ok := strings.Cut(req.ID, "/")
+	if !ok || patternId == "" || value == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: pattern_id/value. Got: %q", req.ID),
+		)
+		return
+	}
+
+	// Read fills in the rest of the entry attributes.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("pattern_id"), patternId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value"), value)...)
+}
+
+// findEntry returns the pattern of the traffic pattern with the value.
+func (r *trafficPatternEntryResource) findEntry(trafficPattern *restapi.TrafficPattern, value string) (*PatternsModel, bool) {
+	patterns, err := r.patterns.TrafficPatternItemModelTransformation(trafficPattern.Patterns, trafficPattern.ContentType)
+	if err != nil {
+		return nil, false
+	}
+	for _, pattern := range patterns {
+		if patternValue(pattern, *trafficPattern.ContentType) == value {
+			return pattern, true
+		}
+	}
+	return nil, false
+}
+
+// replaceEntry replaces the pattern with the value in the traffic pattern with
+// the entry, the pattern is only removed if the entry is nil. The core library
+// could only replace the whole list, so the patterns read from Ngenix are sent
+// back with the entry changed by a single PATCH. The stored pattern of the entry
+// is returned.
+func (r *trafficPatternEntryResource) replaceEntry(ctx context.Context, tpId int, trafficPattern *restapi.TrafficPattern, value string, entry *trafficPatternEntryResourceModel) (*PatternsModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	contentType := *trafficPattern.ContentType
+	stored, err := r.patterns.TrafficPatternItemModelTransformation(trafficPattern.Patterns, trafficPattern.ContentType)
+	if err != nil {
+		diags.AddError("Error while Traffic Pattern entry creation and validation process", err.Error())
+		return nil, diags
+	}
+	patterns := []*PatternsModel{}
+	for _, pattern := range stored {
+		if patternValue(pattern, contentType) != value {
+			patterns = append(patterns, pattern)
+		}
+	}
+	entryIndex := -1
+	if entry != nil {
+		pattern, err := entry.pattern(contentType)
+		if err != nil {
+			diags.AddError("Error while Traffic Pattern entry creation and validation process", err.Error())
+			return nil, diags
+		}
+		entryIndex = len(patterns)
+		patterns = append(patterns, pattern)
+	}
+	apiPatterns, err := r.patterns.TrafficPatternModelTransformation(patterns, contentType)
+	if err != nil {
+		diags.AddError("Error while Traffic Pattern entry creation and validation process", err.Error())
+		return nil, diags
+	}
+
+	updated, err := r.client.UpdateTrafficPatternById(ctx, restapi.TrafficPattern{Patterns: apiPatterns}, tpId)
+	if err != nil {
+		addAPIErrorDiagnostics(&diags, "Error Updating Ngenix Traffic Pattern entry",
+			fmt.Sprintf("Could not update %s in Traffic Pattern with ID = %d (PATCH), unexpected error", value, tpId), err,
+			trafficPatternEntryFieldPath(entryIndex))
+		return nil, diags
+	}
+	if entry == nil {
+		return nil, diags
+	}
+
+	pattern, ok := r.findEntry(updated, entry.Value.ValueString())
+	if !ok {
+		diags.AddError(
+			"Ngenix Traffic Pattern entry not found",
+			fmt.Sprintf("Traffic Pattern with ID = %d does not contain %s after it was updated.", tpId, entry.Value.ValueString()),
+		)
+		return nil, diags
+	}
+	return pattern, diags
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestTrafficPatternEntryResource(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, the entry is added to the seeded list.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "11"
+  value      = "203.0.113.0/24"
+  comment    = "incident 42"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "id", "11/203.0.113.0/24"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "comment", "incident 42"),
+					resource.TestCheckNoResourceAttr("ngenix_traffic_pattern_entry.test", "ttl"),
+					resource.TestCheckResourceAttrSet("ngenix_traffic_pattern_entry.test", "last_updated"),
+				),
+			},
+			// ImportState testing.
+			{
+				ResourceName:            "ngenix_traffic_pattern_entry.test",
+				ImportState:             true,
+				ImportStateId:           "11/203.0.113.0/24",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing, the other entries of the list are left untouched.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "11"
+  value      = "203.0.113.0/24"
+  ttl        = 3600
+}
+
+data "ngenix_traffic_patterns" "test" {
+  depends_on = [ngenix_traffic_pattern_entry.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "ttl", "3600"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "comment", ""),
+					resource.TestCheckTypeSetElemNestedAttrs("data.ngenix_traffic_patterns.test", "traffic_patterns.*", map[string]string{
+						"name":       "seed-commonlist",
+						"patterns.#": "2",
+					}),
+				),
+			},
+			// Delete testing automatically occurs in TestCase.
+		},
+	})
+}
+
+func TestTrafficPatternEntryResourceParallel(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	config := func(comment string) string {
+		return providerConfig + fmt.Sprintf(`
+resource "ngenix_traffic_pattern_entry" "test" {
+  count      = 4
+  pattern_id = "11"
+  value      = "198.18.${count.index}.0/24"
+  comment    = %q
+}
+
+data "ngenix_traffic_patterns" "test" {
+  depends_on = [ngenix_traffic_pattern_entry.test]
+}
+`, comment)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The entries of one list applied in parallel are all added.
+			{
+				Config: config("created"),
+				Check: resource.TestCheckTypeSetElemNestedAttrs("data.ngenix_traffic_patterns.test", "traffic_patterns.*", map[string]string{
+					"name":       "seed-commonlist",
+					"patterns.#": "5",
+				}),
+			},
+			// The entries of one list updated in parallel are all kept.
+			{
+				Config: config("updated"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test.0", "comment", "updated"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test.3", "comment", "updated"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.ngenix_traffic_patterns.test", "traffic_patterns.*", map[string]string{
+						"name":       "seed-commonlist",
+						"patterns.#": "5",
+					}),
+				),
+			},
+			// Delete testing automatically occurs in TestCase.
+		},
+	})
+}
+
+func TestTrafficPatternEntryResourceExpired(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	expires := time.Now().Add(-time.Hour).Unix()
+	config := func(recreateExpired bool) string {
+		return providerConfig + fmt.Sprintf(`
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id       = "11"
+  value            = "198.51.100.0/24"
+  expires          = %d
+  recreate_expired = %t
+}
+`, expires, recreateExpired)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, the expiration time is known from expires.
+			{
+				Config: config(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expiration", time.Unix(expires, 0).UTC().Format(time.RFC3339)),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expired", "false"),
+				),
+			},
+			// The expired entry removed by Ngenix is kept and produces no diff.
+			{
+				PreConfig: func() { testAccAPI.expireTrafficPatternPattern("seed-commonlist", "198.51.100.0/24") },
+				Config:    config(false),
+				Check:     resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expired", "true"),
+			},
+			{
+				Config:   config(false),
+				PlanOnly: true,
+			},
+			// With recreate_expired the expired entry is added again.
+			{
+				Config: config(true),
+				Check:  resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expired", "false"),
+			},
+		},
+	})
+}
+
+func TestTrafficPatternEntryResourceExpiresAt(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	config := func(expiresAt string) string {
+		return providerConfig + fmt.Sprintf(`
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "11"
+  value      = "203.0.113.0/24"
+  expires_at = %q
+}
+`, expiresAt)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, the duration is sent as ttl.
+			{
+				Config: config("72h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expires_at", "72h"),
+					resource.TestCheckNoResourceAttr("ngenix_traffic_pattern_entry.test", "ttl"),
+					resource.TestCheckResourceAttrSet("ngenix_traffic_pattern_entry.test", "expiration"),
+				),
+			},
+			// Update and Read testing, the timestamp is sent as expires.
+			{
+				Config: config(expiresAt),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expires_at", expiresAt),
+					resource.TestCheckNoResourceAttr("ngenix_traffic_pattern_entry.test", "expires"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern_entry.test", "expiration", expiresAt),
+				),
+			},
+		},
+	})
+}
+
+func TestTrafficPatternEntryResourceValidation(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The value is validated against the content type of the list.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "11"
+  value      = "GET"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`not a valid IPv4 or IPv6 CIDR block`),
+			},
+			// Read-only lists could not be changed.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "12"
+  value      = "203.0.113.0/24"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Read-Only`),
+			},
+			// expires_at is either a timestamp or a duration.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "11"
+  value      = "203.0.113.0/24"
+  expires_at = "tomorrow"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`neither an RFC 3339 timestamp nor a duration`),
+			},
+			// Existing entries should be imported.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern_entry" "test" {
+  pattern_id = "11"
+  value      = "10.0.0.0/8"
+}
+`,
+				ExpectError: regexp.MustCompile(`already contains 10.0.0.0/8`),
+			},
+		},
+	})
+}
+
+func TestTrafficPatternEntryFieldPath(t *testing.T) {
+	// The entry is sent after the other patterns of the list.
+	fieldPath := trafficPatternEntryFieldPath(2)
+	if attributePath, ok := fieldPath(apiField{name: "patterns", index: 2, attribute: "ttl"}); !ok || !attributePath.Equal(path.Root("ttl")) {
+		t.Errorf("expected the ttl of the entry, got %q, %t", attributePath, ok)
+	}
+	if attributePath, ok := fieldPath(apiField{name: "patterns", index: 2, attribute: "addr"}); !ok || !attributePath.Equal(path.Root("value")) {
+		t.Errorf("expected the value of the entry, got %q, %t", attributePath, ok)
+	}
+	if _, ok := fieldPath(apiField{name: "patterns", index: 0, attribute: "addr"}); ok {
+		t.Error("expected no path for the other patterns of the list")
+	}
+	if _, ok := trafficPatternEntryFieldPath(-1)(apiField{name: "patterns", index: 0}); ok {
+		t.Error("expected no path without the entry")
+	}
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// parsePatternExpiresAt parses the pattern expires_at, an RFC 3339 timestamp is
+// converted to expires and a duration like 72h to ttl.
+func parsePatternExpiresAt(value string) (expires, ttl types.Int64, err error) {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return types.Int64Value(timestamp.Unix()), types.Int64Null(), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return types.Int64Null(), types.Int64Null(), fmt.Errorf("expires_at value %s is neither an RFC 3339 timestamp nor a duration like 72h", value)
+	}
+	if duration < time.Second {
+		return types.Int64Null(), types.Int64Null(), fmt.Errorf("expires_at duration %s should be at least 1s", value)
+	}
+	return types.Int64Null(), types.Int64Value(int64(duration / time.Second)), nil
+}
+
+// resolvePatternExpirations returns the patterns in the form sent to Ngenix,
+// with expires_at converted to ttl or expires.
+func resolvePatternExpirations(patterns []*PatternsModel) ([]*PatternsModel, error) {
+	resolved := make([]*PatternsModel, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern == nil || pattern.ExpiresAt.IsNull() {
+			resolved = append(resolved, pattern)
+			continue
+		}
+		expires, ttl, err := parsePatternExpiresAt(pattern.ExpiresAt.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		resolvedPattern := *pattern
+		resolvedPattern.Expires, resolvedPattern.Ttl, resolvedPattern.ExpiresAt = expires, ttl, types.StringNull()
+		resolved = append(resolved, &resolvedPattern)
+	}
+	return resolved, nil
+}
+
+// patternExpiration returns the absolute expiration time of the pattern sent to
+// Ngenix. The expiration time of a pattern with ttl is known only if the pattern
+// was added by Terraform.
+func (m *TrafficPatternResourceModel) patternExpiration(pattern *PatternsModel) (time.Time, bool) {
+	if !pattern.Expires.IsNull() {
+		return time.Unix(pattern.Expires.ValueInt64(), 0), true
+	}
+	if pattern.Ttl.IsNull() || m.Expirations.IsNull() || m.Expirations.IsUnknown() {
+		return time.Time{}, false
+	}
+	expiration, ok := m.Expirations.Elements()[patternValue(pattern, m.ContentType.ValueString())].(types.String)
+	if !ok {
+		return time.Time{}, false
+	}
+	timestamp, err := time.Parse(time.RFC3339, expiration.ValueString())
+	return timestamp, err == nil
+}
+
+// setExpirations records the absolute expiration times of the patterns, the
+// added patterns with ttl expire in ttl from now.
+func (m *TrafficPatternResourceModel) setExpirations(patterns, added []*PatternsModel, now time.Time) {
+	addedKeys := map[string]bool{}
+	for _, pattern := range added {
+		if pattern != nil {
+			addedKeys[patternKey(pattern)] = true
+		}
+	}
+
+	expirations := map[string]attr.Value{}
+	for _, pattern := range patterns {
+		if pattern == nil || (pattern.Ttl.IsNull() && pattern.Expires.IsNull()) {
+			continue
+		}
+		expiration, ok := m.patternExpiration(pattern)
+		if !pattern.Ttl.IsNull() && addedKeys[patternKey(pattern)] {
+			expiration, ok = now.Add(time.Duration(pattern.Ttl.ValueInt64())*time.Second), true
+		}
+		if ok {
+			expirations[patternValue(pattern, m.ContentType.ValueString())] = types.StringValue(expiration.UTC().Format(time.RFC3339))
+		}
+	}
+	m.Expirations = types.MapValueMust(types.StringType, expirations)
+}
+
+// keepExpiredPatterns compares the patterns read from Ngenix with the applied
+// ones and lists the expired patterns removed by Ngenix in expired_patterns.
+// Unless recreate_expired is set, the expired patterns are kept, so they are
+// not planned to be added again.
+func (m *TrafficPatternResourceModel) keepExpiredPatterns(patterns []*PatternsModel, now time.Time) []*PatternsModel {
+	expired := []attr.Value{}
+	defer func() {
+		m.ExpiredPatterns = types.ListValueMust(types.StringType, expired)
+	}()
+
+	applied, ok := m.appliedPatterns()
+	if !ok {
+		// The patterns loaded from a source are not stored in the state, the
+		// expired ones are looked up in the source.
+		if applied, ok = m.sourcePatterns(); !ok {
+			return patterns
+		}
+	}
+	missing, _ := diffPatterns(patterns, applied)
+	for _, pattern := range missing {
+		if expiration, ok := m.patternExpiration(pattern); ok && !expiration.After(now) {
+			expired = append(expired, types.StringValue(patternValue(pattern, m.ContentType.ValueString())))
+			if !m.RecreateExpired.ValueBool() {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// expiredPatterns returns the patterns listed in expired_patterns, which are
+// still expired, so a pattern with a new expiration time is added again.
+func (m *TrafficPatternResourceModel) expiredPatterns(patterns []*PatternsModel, now time.Time) []*PatternsModel {
+	values := map[string]bool{}
+	for _, value := range m.ExpiredPatterns.Elements() {
+		if value, ok := value.(types.String); ok {
+			values[value.ValueString()] = true
+		}
+	}
+
+	expired := []*PatternsModel{}
+	for _, pattern := range patterns {
+		if pattern == nil || !values[patternValue(pattern, m.ContentType.ValueString())] {
+			continue
+		}
+		if expiration, ok := m.patternExpiration(pattern); ok && !expiration.After(now) {
+			expired = append(expired, pattern)
+		}
+	}
+	return expired
+}
+
+// setExpiration records the absolute expiration time of the entry, an added
+// entry with ttl expires in ttl from now. The entry stored by Ngenix is not
+// expired.
+func (m *trafficPatternEntryResourceModel) setExpiration(added bool, now time.Time) {
+	m.Expired = types.BoolValue(false)
+	ttl, expires, err := m.expiry()
+	switch {
+	case err != nil:
+		m.Expiration = types.StringNull()
+	case !expires.IsNull():
+		m.Expiration = types.StringValue(time.Unix(expires.ValueInt64(), 0).UTC().Format(time.RFC3339))
+	case !ttl.IsNull() && added:
+		m.Expiration = types.StringValue(now.Add(time.Duration(ttl.ValueInt64()) * time.Second).UTC().Format(time.RFC3339))
+	case !ttl.IsNull() && !m.Expiration.IsUnknown():
+		// The expiration time of the entry added before is kept.
+	default:
+		m.Expiration = types.StringNull()
+	}
+}
+
+// isExpired reports whether the expiration time of the entry lapsed.
+func (m *trafficPatternEntryResourceModel) isExpired(now time.Time) bool {
+	if m.Expiration.IsNull() || m.Expiration.IsUnknown() {
+		return false
+	}
+	expiration, err := time.Parse(time.RFC3339, m.Expiration.ValueString())
+	return err == nil && !expiration.After(now)
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParsePatternExpiresAt(t *testing.T) {
+	testCases := []struct {
+		value   string
+		expires types.Int64
+		ttl     types.Int64
+		valid   bool
+	}{
+		{"2030-01-02T03:04:05Z", types.Int64Value(1893553445), types.Int64Null(), true},
+		{"2030-01-02T06:04:05+03:00", types.Int64Value(1893553445), types.Int64Null(), true},
+		{"72h", types.Int64Null(), types.Int64Value(259200), true},
+		{"1h30m", types.Int64Null(), types.Int64Value(5400), true},
+		{"500ms", types.Int64Null(), types.Int64Null(), false},
+		{"2030-01-02", types.Int64Null(), types.Int64Null(), false},
+		{"3d", types.Int64Null(), types.Int64Null(), false},
+	}
+
+	for _, testCase := range testCases {
+		expires, ttl, err := parsePatternExpiresAt(testCase.value)
+		if testCase.valid != (err == nil) {
+			t.Errorf("%s: unexpected error: %v", testCase.value, err)
+			continue
+		}
+		if !expires.Equal(testCase.expires) || !ttl.Equal(testCase.ttl) {
+			t.Errorf("%s: expected expires %s and ttl %s, got: %s and %s", testCase.value, testCase.expires, testCase.ttl, expires, ttl)
+		}
+	}
+}
+
+func TestKeepExpiredPatterns(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	pattern := func(addr string, ttl, expires types.Int64) *PatternsModel {
+		return &PatternsModel{Addr: types.StringValue(addr), Ttl: ttl, Expires: expires, Comment: types.StringValue("")}
+	}
+	permanent := pattern("192.0.2.0/24", types.Int64Null(), types.Int64Null())
+	expiredTtl := pattern("198.51.100.0/24", types.Int64Value(3600), types.Int64Null())
+	expiredExpires := pattern("203.0.113.0/24", types.Int64Null(), types.Int64Value(now.Add(-time.Minute).Unix()))
+	activeExpires := pattern("2001:db8::/32", types.Int64Null(), types.Int64Value(now.Add(time.Hour).Unix()))
+	unknownTtl := pattern("2001:db8:1::/48", types.Int64Value(3600), types.Int64Null())
+
+	for _, recreateExpired := range []bool{false, true} {
+		m := &TrafficPatternResourceModel{
+			ContentType:     types.StringValue("addr"),
+			Patterns:        []*PatternsModel{permanent, expiredTtl, expiredExpires, activeExpires, unknownTtl},
+			RecreateExpired: types.BoolValue(recreateExpired),
+			Expirations: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"198.51.100.0/24": types.StringValue(now.Add(-time.Second).Format(time.RFC3339)),
+			}),
+		}
+
+		// Only the permanent pattern is still stored by Ngenix.
+		patterns := m.keepExpiredPatterns([]*PatternsModel{permanent}, now)
+
+		expected := []*PatternsModel{permanent, expiredTtl, expiredExpires}
+		if recreateExpired {
+			expected = []*PatternsModel{permanent}
+		}
+		if !samePatterns(patterns, expected) {
+			t.Errorf("recreate_expired = %t: unexpected patterns: %v", recreateExpired, patternKeys(patterns))
+		}
+		expectedExpired := types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("198.51.100.0/24"),
+			types.StringValue("203.0.113.0/24"),
+		})
+		if !m.ExpiredPatterns.Equal(expectedExpired) {
+			t.Errorf("recreate_expired = %t: unexpected expired patterns: %s", recreateExpired, m.ExpiredPatterns)
+		}
+	}
+}
+
+func TestKeepExpiredSourcePatterns(t *testing.T) {
+	// setPatterns compares the expiration times with the current time.
+	now := time.Now()
+	expires := now.Add(-time.Minute).Unix()
+	m := &TrafficPatternResourceModel{
+		ContentType:     types.StringValue("addr"),
+		SourceContent:   types.StringValue(fmt.Sprintf("addr,expires\n192.0.2.0/24,\n203.0.113.0/24,%d\n", expires)),
+		SourceFormat:    types.StringValue("csv"),
+		RecreateExpired: types.BoolValue(false),
+		Expirations:     types.MapNull(types.StringType),
+	}
+	permanent := &PatternsModel{Addr: types.StringValue("192.0.2.0/24"), Comment: types.StringValue("")}
+	expired := &PatternsModel{Addr: types.StringValue("203.0.113.0/24"), Expires: types.Int64Value(expires), Comment: types.StringValue("")}
+
+	// The expired pattern of the source is kept, so the source hash is not changed.
+	m.setPatterns([]*PatternsModel{permanent})
+	if expected := patternsHash([]*PatternsModel{permanent, expired}); m.SourceHash.ValueString() != expected {
+		t.Errorf("unexpected source hash %s, expected: %s", m.SourceHash, expected)
+	}
+	expectedExpired := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("203.0.113.0/24")})
+	if !m.ExpiredPatterns.Equal(expectedExpired) {
+		t.Errorf("unexpected expired patterns: %s", m.ExpiredPatterns)
+	}
+	if patterns := m.expiredPatterns([]*PatternsModel{permanent, expired}, now); !samePatterns(patterns, []*PatternsModel{expired}) {
+		t.Errorf("unexpected still expired patterns: %v", patternKeys(patterns))
+	}
+
+	// A pattern with a new expiration time is not expired anymore.
+	renewed := &PatternsModel{Addr: expired.Addr, Expires: types.Int64Value(now.Add(time.Hour).Unix()), Comment: types.StringValue("")}
+	if patterns := m.expiredPatterns([]*PatternsModel{renewed}, now); len(patterns) != 0 {
+		t.Errorf("expected no expired patterns, got: %v", patternKeys(patterns))
+	}
+}
+
+func TestSetExpirations(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	added := &PatternsModel{Addr: types.StringValue("192.0.2.0/24"), Ttl: types.Int64Value(3600)}
+	known := &PatternsModel{Addr: types.StringValue("198.51.100.0/24"), Ttl: types.Int64Value(60)}
+	expires := &PatternsModel{Addr: types.StringValue("203.0.113.0/24"), Expires: types.Int64Value(now.Unix())}
+	imported := &PatternsModel{Addr: types.StringValue("2001:db8::/32"), Ttl: types.Int64Value(60)}
+
+	m := &TrafficPatternResourceModel{
+		ContentType: types.StringValue("addr"),
+		Expirations: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"198.51.100.0/24": types.StringValue("2029-12-31T00:00:00Z"),
+		}),
+	}
+	m.setExpirations([]*PatternsModel{added, known, expires, imported}, []*PatternsModel{added}, now)
+
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"192.0.2.0/24":    types.StringValue("2030-01-01T01:00:00Z"),
+		"198.51.100.0/24": types.StringValue("2029-12-31T00:00:00Z"),
+		"203.0.113.0/24":  types.StringValue("2030-01-01T00:00:00Z"),
+	})
+	if !m.Expirations.Equal(expected) {
+		t.Errorf("unexpected expirations: %s", m.Expirations)
+	}
+}
+
+func TestTrafficPatternEntryExpiration(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	entry := &trafficPatternEntryResourceModel{Ttl: types.Int64Value(3600), Expiration: types.StringUnknown()}
+	entry.setExpiration(true, now)
+	if entry.Expiration.ValueString() != "2030-01-01T01:00:00Z" || entry.Expired.ValueBool() {
+		t.Errorf("unexpected expiration of the added entry: %s, %s", entry.Expiration, entry.Expired)
+	}
+	// The expiration time of the entry with ttl is kept on refresh.
+	entry.setExpiration(false, now.Add(time.Minute))
+	if entry.Expiration.ValueString() != "2030-01-01T01:00:00Z" {
+		t.Errorf("unexpected expiration of the refreshed entry: %s", entry.Expiration)
+	}
+	if entry.isExpired(now) || !entry.isExpired(now.Add(time.Hour)) {
+		t.Error("expected the entry to expire in an hour")
+	}
+
+	imported := &trafficPatternEntryResourceModel{Ttl: types.Int64Value(3600), Expiration: types.StringNull()}
+	imported.setExpiration(false, now)
+	if !imported.Expiration.IsNull() || imported.isExpired(now.Add(2*time.Hour)) {
+		t.Errorf("expected unknown expiration of the imported entry, got: %s", imported.Expiration)
+	}
+
+	expires := &trafficPatternEntryResourceModel{Expires: types.Int64Value(now.Unix()), Ttl: types.Int64Null()}
+	expires.setExpiration(false, now)
+	if expires.Expiration.ValueString() != "2030-01-01T00:00:00Z" || !expires.isExpired(now) {
+		t.Errorf("unexpected expiration of the entry with expires: %s", expires.Expiration)
+	}
+
+	expiresAt := &trafficPatternEntryResourceModel{ExpiresAt: types.StringValue("2h"), Ttl: types.Int64Null(), Expires: types.Int64Null()}
+	expiresAt.setExpiration(true, now)
+	if expiresAt.Expiration.ValueString() != "2030-01-01T02:00:00Z" {
+		t.Errorf("unexpected expiration of the entry with expires_at: %s", expiresAt.Expiration)
+	}
+}
+
+func TestTrafficPatternEntryExpiresAt(t *testing.T) {
+	entry := &trafficPatternEntryResourceModel{
+		PatternID: types.StringValue("11"),
+		Value:     types.StringValue("192.0.2.0/24"),
+		Ttl:       types.Int64Null(),
+		Expires:   types.Int64Null(),
+		ExpiresAt: types.StringValue("2030-01-01T00:00:00Z"),
+		Comment:   types.StringValue(""),
+	}
+	pattern, err := entry.pattern("addr")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pattern.Expires.ValueInt64() != time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Unix() || !pattern.Ttl.IsNull() {
+		t.Errorf("expected expires_at sent as expires, got ttl %s, expires %s", pattern.Ttl, pattern.Expires)
+	}
+
+	// expires_at is kept while Ngenix stores the expires it was sent as.
+	entry.setPattern(pattern)
+	if entry.ExpiresAt.ValueString() != "2030-01-01T00:00:00Z" || !entry.Expires.IsNull() {
+		t.Errorf("expected expires_at to be kept, got expires_at %s, expires %s", entry.ExpiresAt, entry.Expires)
+	}
+	// The expiration changed outside of Terraform is shown as expires.
+	changed := *pattern
+	changed.Expires = types.Int64Value(pattern.Expires.ValueInt64() + 60)
+	entry.setPattern(&changed)
+	if !entry.ExpiresAt.IsNull() || !entry.Expires.Equal(changed.Expires) {
+		t.Errorf("expected the stored expires, got expires_at %s, expires %s", entry.ExpiresAt, entry.Expires)
+	}
+
+	entry.ExpiresAt = types.StringValue("tomorrow")
+	if _, err := entry.pattern("addr"); err == nil {
+		t.Error("expected the invalid expires_at error")
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// normalizeAddrPatterns canonicalizes addr patterns to their network addresses
+// and collapses duplicates, overlapping and adjacent CIDR blocks to the minimal
+// set of blocks. Only patterns with equal ttl, expires and comment are collapsed,
+// the groups of such patterns keep the order of the configuration.
+func normalizeAddrPatterns(patterns []*PatternsModel) ([]*PatternsModel, error) {
+	type patternGroup struct {
+		pattern  *PatternsModel
+		prefixes []netip.Prefix
+	}
+	groups := []*patternGroup{}
+	for _, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+		prefix, err := patternNetworkAddress(pattern.Addr.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		var group *patternGroup
+		for _, g := range groups {
+			if g.pattern.Ttl.Equal(pattern.Ttl) && g.pattern.Expires.Equal(pattern.Expires) && g.pattern.Comment.Equal(pattern.Comment) {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &patternGroup{pattern: pattern}
+			groups = append(groups, group)
+		}
+		group.prefixes = append(group.prefixes, prefix)
+	}
+
+	normalized := []*PatternsModel{}
+	for _, group := range groups {
+		for _, prefix := range collapsePrefixes(group.prefixes) {
+			normalized = append(normalized, &PatternsModel{
+				Addr:    types.StringValue(prefix.String()),
+				Ttl:     group.pattern.Ttl,
+				Expires: group.pattern.Expires,
+				Comment: group.pattern.Comment,
+			})
+		}
+	}
+	return normalized, nil
+}
+
+// collapsePrefixes returns the minimal sorted set of network prefixes covering
+// the same addresses as the prefixes.
+func collapsePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		sorted = append(sorted, prefix.Masked())
+	}
+	slices.SortFunc(sorted, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return a.Bits() - b.Bits()
+	})
+
+	collapsed := []netip.Prefix{}
+	for _, prefix := range sorted {
+		// Sorted prefixes could only be covered by the last collapsed one.
+		if last := len(collapsed) - 1; last >= 0 && collapsed[last].Bits() <= prefix.Bits() && collapsed[last].Contains(prefix.Addr()) {
+			continue
+		}
+		collapsed = append(collapsed, prefix)
+
+		// Merge the lower and the upper halves of the same parent prefix.
+		for len(collapsed) >= 2 {
+			lower, upper := collapsed[len(collapsed)-2], collapsed[len(collapsed)-1]
+			if lower.Bits() != upper.Bits() || lower.Bits() == 0 {
+				break
+			}
+			parent, _ := lower.Addr().Prefix(lower.Bits() - 1)
+			if parent.Addr() != lower.Addr() || !parent.Contains(upper.Addr()) {
+				break
+			}
+			collapsed = append(collapsed[:len(collapsed)-2], parent)
+		}
+	}
+	return collapsed
+}
+
+// samePatterns reports whether both lists have the same patterns in any order.
+func samePatterns(a, b []*PatternsModel) bool {
+	return slices.Equal(patternKeys(a), patternKeys(b))
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCollapsePrefixes(t *testing.T) {
+	testCases := []struct {
+		prefixes []string
+		expected []string
+	}{
+		{[]string{"10.0.0.0/24", "10.0.0.0/24"}, []string{"10.0.0.0/24"}},
+		{[]string{"10.0.0.0/16", "10.0.5.0/24"}, []string{"10.0.0.0/16"}},
+		{[]string{"10.0.1.0/24", "10.0.0.0/24"}, []string{"10.0.0.0/23"}},
+		{[]string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/23"}, []string{"10.0.0.0/22"}},
+		{[]string{"10.0.1.0/24", "10.0.2.0/24"}, []string{"10.0.1.0/24", "10.0.2.0/24"}},
+		{[]string{"0.0.0.0/1", "128.0.0.0/1"}, []string{"0.0.0.0/0"}},
+		{[]string{"2001:db8:8000::/33", "2001:db8::/33", "10.0.0.0/8"}, []string{"10.0.0.0/8", "2001:db8::/32"}},
+	}
+
+	for _, testCase := range testCases {
+		prefixes := []netip.Prefix{}
+		for _, prefix := range testCase.prefixes {
+			prefixes = append(prefixes, netip.MustParsePrefix(prefix))
+		}
+		collapsed := []string{}
+		for _, prefix := range collapsePrefixes(prefixes) {
+			collapsed = append(collapsed, prefix.String())
+		}
+		if !slices.Equal(collapsed, testCase.expected) {
+			t.Errorf("%v: expected %v, got %v", testCase.prefixes, testCase.expected, collapsed)
+		}
+	}
+}
+
+func TestNormalizeAddrPatterns(t *testing.T) {
+	pattern := func(addr string, ttl types.Int64) *PatternsModel {
+		return &PatternsModel{Addr: types.StringValue(addr), Ttl: ttl, Expires: types.Int64Null(), Comment: types.StringValue("")}
+	}
+	patterns := []*PatternsModel{
+		pattern("10.1.2.3/24", types.Int64Null()),
+		pattern("10.1.3.0/24", types.Int64Null()),
+		pattern("10.1.2.128/25", types.Int64Null()),
+		// Patterns with another TTL are not collapsed with the previous ones.
+		pattern("10.1.4.0/24", types.Int64Value(3600)),
+		pattern("10.1.4.7/32", types.Int64Value(3600)),
+	}
+	expected := []*PatternsModel{
+		pattern("10.1.2.0/23", types.Int64Null()),
+		pattern("10.1.4.0/24", types.Int64Value(3600)),
+	}
+
+	normalized, err := normalizeAddrPatterns(patterns)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !samePatterns(normalized, expected) {
+		t.Errorf("unexpected normalized patterns: %v", normalized)
+	}
+
+	if _, err := normalizeAddrPatterns([]*PatternsModel{pattern("10.1.2.3", types.Int64Null())}); err == nil {
+		t.Error("expected an error for the address without netmask")
+	}
+}
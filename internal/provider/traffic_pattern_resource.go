@@ -3,15 +3,19 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"regexp"
 	"strconv"
 	"time"
 
 	"ngenix/restapi"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -22,9 +26,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &trafficPatternResource{}
-	_ resource.ResourceWithConfigure   = &trafficPatternResource{}
-	_ resource.ResourceWithImportState = &trafficPatternResource{}
+	_ resource.Resource                   = &trafficPatternResource{}
+	_ resource.ResourceWithConfigure      = &trafficPatternResource{}
+	_ resource.ResourceWithImportState    = &trafficPatternResource{}
+	_ resource.ResourceWithValidateConfig = &trafficPatternResource{}
+	_ resource.ResourceWithModifyPlan     = &trafficPatternResource{}
 )
 
 // TrafficPatternResource is a helper function to simplify the provider implementation.
@@ -34,17 +40,27 @@ func TrafficPatternResource() resource.Resource {
 
 // TrafficPatternDataSource is the data source implementation.
 type trafficPatternResource struct {
-	client *restapi.Client
+	client *ngenixClient
 }
 
 // TrafficPatternResourceModel maps schema data.
 type TrafficPatternResourceModel struct {
-	ID          types.String     `tfsdk:"id"`
-	Name        types.String     `tfsdk:"name"`
-	Type        types.String     `tfsdk:"type"`
-	ContentType types.String     `tfsdk:"content_type"`
-	Patterns    []*PatternsModel `tfsdk:"patterns"`
-	LastUpdated types.String     `tfsdk:"last_updated"`
+	ID                 types.String     `tfsdk:"id"`
+	Name               types.String     `tfsdk:"name"`
+	Type               types.String     `tfsdk:"type"`
+	ContentType        types.String     `tfsdk:"content_type"`
+	Patterns           []*PatternsModel `tfsdk:"patterns"`
+	SourceFile         types.String     `tfsdk:"source_file"`
+	SourceContent      types.String     `tfsdk:"source_content"`
+	SourceFormat       types.String     `tfsdk:"source_format"`
+	SourceHash         types.String     `tfsdk:"source_hash"`
+	NormalizeAddresses types.Bool       `tfsdk:"normalize_addresses"`
+	NormalizedPatterns []*PatternsModel `tfsdk:"normalized_patterns"`
+	RecreateExpired    types.Bool       `tfsdk:"recreate_expired"`
+	Expirations        types.Map        `tfsdk:"expirations"`
+	ExpiredPatterns    types.List       `tfsdk:"expired_patterns"`
+	LastUpdated        types.String     `tfsdk:"last_updated"`
+	Timeouts           timeouts.Value   `tfsdk:"timeouts"`
 }
 
 // TrafficPatternsModel maps schema data.
@@ -57,6 +73,7 @@ type PatternsModel struct {
 	Md5HashString types.String `tfsdk:"md5hash_string"`
 	Ttl           types.Int64  `tfsdk:"ttl"`
 	Expires       types.Int64  `tfsdk:"expires"`
+	ExpiresAt     types.String `tfsdk:"expires_at"`
 	Comment       types.String `tfsdk:"comment"`
 }
 
@@ -90,11 +107,11 @@ func (r *trafficPatternResource) Configure(_ context.Context, req resource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*restapi.Client)
+	client, ok := req.ProviderData.(*ngenixClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *restapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -109,7 +126,7 @@ func (r *trafficPatternResource) Metadata(_ context.Context, req resource.Metada
 }
 
 // Schema defines the schema for the data source.
-func (r *trafficPatternResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *trafficPatternResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a Traffic Pattern.",
 		Attributes: map[string]schema.Attribute{
@@ -133,17 +150,25 @@ func (r *trafficPatternResource) Schema(_ context.Context, req resource.SchemaRe
 			},
 			"type": schema.StringAttribute{ // [ blacklist, whitelist, filterlist, commonlist ]
 				Required:    true,
-				Description: "Traffic pattern type",
+				Description: "Traffic pattern type. Changing the type re-creates the traffic pattern.",
 				Validators: []validator.String{
 					stringvalidator.OneOf([]string{"blacklist", "whitelist", "filterlist", "commonlist"}...),
 				},
+				// The type could not be changed by PATCH.
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"content_type": schema.StringAttribute{ // [ addr, commonString, countryCode, httpMethod, asn, md5HashString ]
 				Required:    true,
-				Description: "Traffic pattern content type",
+				Description: "Traffic pattern content type. Changing the content type re-creates the traffic pattern.",
 				Validators: []validator.String{
 					stringvalidator.OneOf([]string{"addr", "commonString", "countryCode", "httpMethod", "asn", "md5HashString"}...),
 				},
+				// The content type could not be changed by PATCH.
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"patterns": schema.ListNestedAttribute{
 				Description: "A list of Traffic patterns.",
@@ -152,7 +177,7 @@ func (r *trafficPatternResource) Schema(_ context.Context, req resource.SchemaRe
 					Attributes: map[string]schema.Attribute{
 						"addr": schema.StringAttribute{
 							Optional:    true,
-							Description: "Traffic pattern CIDR block - network CIDR block after validation",
+							Description: "Traffic pattern IPv4 or IPv6 CIDR block, e.g. 192.0.2.0/24 or 2001:db8::/32. It should be the network address of the block in the canonical form, unless normalize_addresses is set.",
 						},
 						"common_string": schema.StringAttribute{
 							Optional:    true,
@@ -182,6 +207,11 @@ func (r *trafficPatternResource) Schema(_ context.Context, req resource.SchemaRe
 							Optional:    true,
 							Description: "Traffic pattern expiration date",
 						},
+						"expires_at": schema.StringAttribute{
+							Optional: true,
+							Description: "Traffic pattern expiration as an RFC 3339 timestamp, e.g. 2025-12-31T23:59:59Z, sent as expires, " +
+								"or as a duration, e.g. 72h, sent as ttl. Conflicts with ttl and expires.",
+						},
 						"comment": schema.StringAttribute{
 							Optional:    true,
 							Computed:    true,
@@ -191,8 +221,91 @@ func (r *trafficPatternResource) Schema(_ context.Context, req resource.SchemaRe
 					},
 				},
 			},
+			"source_file": schema.StringAttribute{
+				Description: "Path to a file with the traffic patterns, used instead of patterns. " +
+					"The file is parsed according to content_type and source_format, only its hash is stored in the state.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("patterns"), path.MatchRoot("source_content")),
+				},
+			},
+			"source_content": schema.StringAttribute{
+				Description: "Traffic patterns in the source_format, used instead of patterns, " +
+					"e.g. the result of the file() or templatefile() function. Only its hash is stored in the state.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("patterns")),
+				},
+			},
+			"source_format": schema.StringAttribute{
+				Description: "Format of source_file or source_content: text - one value per line with an optional # comment, " +
+					"csv - a header row with the pattern attribute names, e.g. addr,ttl,comment, " +
+					"json - an array of values or of objects with the pattern attribute names. " +
+					"Detected by the source_file extension (.csv, .json), text by default.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(PatternSourceFormats...),
+				},
+			},
+			"source_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the traffic patterns loaded from source_file or source_content, " +
+					"a changed hash in the plan means that the patterns sent to Ngenix are changed.",
+				Computed: true,
+			},
+			"normalize_addresses": schema.BoolAttribute{
+				Description: "Canonicalize addr patterns to their network addresses and collapse duplicates, overlapping and adjacent CIDR blocks " +
+					"with equal ttl, expires and comment to the minimal set of blocks before sending them to Ngenix. " +
+					"The normalized list is shown in normalized_patterns.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"recreate_expired": schema.BoolAttribute{
+				Description: "Add the patterns removed by Ngenix after their ttl or expires lapsed again on the next apply. " +
+					"By default the expired patterns are kept in the state and listed in expired_patterns, so they produce no diff.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"expirations": schema.MapAttribute{
+				Description: "Absolute expiration times of the patterns with ttl or expires in RFC 3339 format by the pattern values. " +
+					"The expiration time of a pattern with ttl is known only if the pattern was added by Terraform.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"expired_patterns": schema.ListAttribute{
+				Description: "Values of the patterns removed by Ngenix after their ttl or expires lapsed.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"normalized_patterns": schema.ListNestedAttribute{
+				Description: "Traffic patterns sent to Ngenix after normalization, set only with normalize_addresses.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"addr":           schema.StringAttribute{Computed: true, Description: "Traffic pattern CIDR block"},
+						"common_string":  schema.StringAttribute{Computed: true, Description: "Traffic pattern common string"},
+						"country_code":   schema.StringAttribute{Computed: true, Description: "Traffic pattern country code"},
+						"http_method":    schema.StringAttribute{Computed: true, Description: "Traffic pattern HTTP method"},
+						"asn":            schema.Int64Attribute{Computed: true, Description: "Traffic pattern ASN"},
+						"md5hash_string": schema.StringAttribute{Computed: true, Description: "Traffic pattern MD5 hash string"},
+						"ttl":            schema.Int64Attribute{Computed: true, Description: "Traffic pattern TTL"},
+						"expires":        schema.Int64Attribute{Computed: true, Description: "Traffic pattern expiration date"},
+						"expires_at":     schema.StringAttribute{Computed: true, Description: "Always null, expires_at is sent as ttl or expires"},
+						"comment":        schema.StringAttribute{Computed: true, Description: "Traffic pattern comment"},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
-		Blocks: map[string]schema.Block{},
 	}
 }
 
@@ -328,71 +441,83 @@ func (r *trafficPatternResource) TrafficPatternItemModelTransformation(trafficPa
 	return patternsModel, nil
 }
 
+// PatternContentTypeAttributes maps content types to the pattern attribute holding the value.
+var PatternContentTypeAttributes = map[string]string{
+	"addr":          "addr",
+	"commonString":  "common_string",
+	"countryCode":   "country_code",
+	"httpMethod":    "http_method",
+	"asn":           "asn",
+	"md5HashString": "md5hash_string",
+}
+
 // This function validates:
 // 1. pattern field based on provided content type.
 // 2. pattern fields base validattion for ip addr / max characters / match in a range and others ...
-func (r *trafficPatternResource) validateContentTypeWithPattern(contentType *string, patterns []*PatternsModel) error {
+// Unknown values are skipped, they are validated again once they are known.
+func (r *trafficPatternResource) validateContentTypeWithPattern(contentType string, pattern *PatternsModel) error {
 	switch {
-	case *contentType == "addr":
-		for _, pattern := range patterns {
-			if pattern.Addr.IsNull() || pattern.Addr.IsUnknown() {
-				return fmt.Errorf("pattern must contain addr field for content type 'addr'")
-			}
-			ipRegex := regexp.MustCompile(`^(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])(\/(3[0-2]|[1-2]?\d))?$`)
-			if !ipRegex.MatchString(pattern.Addr.ValueString()) {
-				return fmt.Errorf("addr value is not a valid IP address")
-			}
+	case contentType == "addr":
+		if pattern.Addr.IsUnknown() {
+			return nil
 		}
-	case *contentType == "commonString":
-		for _, pattern := range patterns {
-			if pattern.CommonString.IsNull() || pattern.CommonString.IsUnknown() {
-				return fmt.Errorf("pattern must contain common_string field for content type 'commonString'")
-			}
-			if len(pattern.CommonString.ValueString()) > 255 {
-				return fmt.Errorf("common_string value exceeds 255 characters")
-			}
+		if pattern.Addr.IsNull() {
+			return fmt.Errorf("pattern must contain addr field for content type 'addr'")
 		}
-	case *contentType == "countryCode":
-		for _, pattern := range patterns {
-			if pattern.CountryCode.IsNull() || pattern.CountryCode.IsUnknown() {
-				return fmt.Errorf("pattern must contain country_code field for content type 'countryCode'")
-			}
-			ccRegex := regexp.MustCompile(`^[A-Z]{2}$`)
-			if !ccRegex.MatchString(pattern.CountryCode.ValueString()) {
-				return fmt.Errorf("country_code value is not valid")
-			}
+		if _, err := patternNetworkAddress(pattern.Addr.ValueString()); err != nil {
+			return err
 		}
-	case *contentType == "httpMethod":
-		validMethods := map[string]bool{
-			"GET": true, "POST": true, "OPTIONS": true, "PUT": true,
-			"PATCH": true, "DELETE": true, "HEAD": true, "PURGE": true,
+	case contentType == "commonString":
+		if pattern.CommonString.IsUnknown() {
+			return nil
 		}
-		for _, pattern := range patterns {
-			if pattern.HttpMethod.IsNull() || pattern.HttpMethod.IsUnknown() {
-				return fmt.Errorf("pattern must contain http_method field for content type 'httpMethod'")
-			}
-			if !validMethods[pattern.HttpMethod.ValueString()] {
-				return fmt.Errorf("http_method value is not valid")
-			}
+		if pattern.CommonString.IsNull() {
+			return fmt.Errorf("pattern must contain common_string field for content type 'commonString'")
 		}
-	case *contentType == "asn":
-		for _, pattern := range patterns {
-			if pattern.Asn.IsNull() || pattern.Asn.IsUnknown() {
-				return fmt.Errorf("pattern must contain asn field for content type 'asn'")
-			}
-			if pattern.Asn.ValueInt64() < 0 || pattern.Asn.ValueInt64() > 4294967295 {
-				return fmt.Errorf("asn value is not valid")
-			}
+		if len(pattern.CommonString.ValueString()) > 255 {
+			return fmt.Errorf("common_string value exceeds 255 characters")
 		}
-	case *contentType == "md5HashString":
-		for _, pattern := range patterns {
-			if pattern.Md5HashString.IsNull() || pattern.Md5HashString.IsUnknown() {
-				return fmt.Errorf("pattern must contain md5hash_string field for content type 'md5HashString'")
-			}
-			md5Regex := regexp.MustCompile(`^[a-fA-F0-9]{32}$`)
-			if !md5Regex.MatchString(pattern.Md5HashString.ValueString()) {
-				return fmt.Errorf("md5hash_string value is not valid")
-			}
+	case contentType == "countryCode":
+		if pattern.CountryCode.IsUnknown() {
+			return nil
+		}
+		if pattern.CountryCode.IsNull() {
+			return fmt.Errorf("pattern must contain country_code field for content type 'countryCode'")
+		}
+		ccRegex := regexp.MustCompile(`^[A-Z]{2}$`)
+		if !ccRegex.MatchString(pattern.CountryCode.ValueString()) {
+			return fmt.Errorf("country_code value is not valid")
+		}
+	case contentType == "httpMethod":
+		if pattern.HttpMethod.IsUnknown() {
+			return nil
+		}
+		if pattern.HttpMethod.IsNull() {
+			return fmt.Errorf("pattern must contain http_method field for content type 'httpMethod'")
+		}
+		if !restapi.IsValueInRange(pattern.HttpMethod.ValueString(), PatternHttpMethods) {
+			return fmt.Errorf("http_method value is not valid")
+		}
+	case contentType == "asn":
+		if pattern.Asn.IsUnknown() {
+			return nil
+		}
+		if pattern.Asn.IsNull() {
+			return fmt.Errorf("pattern must contain asn field for content type 'asn'")
+		}
+		if pattern.Asn.ValueInt64() < 0 || pattern.Asn.ValueInt64() > 4294967295 {
+			return fmt.Errorf("asn value is not valid")
+		}
+	case contentType == "md5HashString":
+		if pattern.Md5HashString.IsUnknown() {
+			return nil
+		}
+		if pattern.Md5HashString.IsNull() {
+			return fmt.Errorf("pattern must contain md5hash_string field for content type 'md5HashString'")
+		}
+		md5Regex := regexp.MustCompile(`^[a-fA-F0-9]{32}$`)
+		if !md5Regex.MatchString(pattern.Md5HashString.ValueString()) {
+			return fmt.Errorf("md5hash_string value is not valid")
 		}
 	default:
 		return fmt.Errorf("content_type value is not valid")
@@ -400,115 +525,393 @@ func (r *trafficPatternResource) validateContentTypeWithPattern(contentType *str
 	return nil
 }
 
-// Create creates the resource and sets the initial Terraform state.
-func (r *trafficPatternResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// Retrieve values from plan.
-	var plan TrafficPatternResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+// ValidateConfig checks the traffic pattern requirements at plan time, so every
+// violation is reported with the path of the offending attribute before apply.
+func (r *trafficPatternResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var tpType, contentType types.String
+	var patternsList types.List
+	var normalizeAddresses types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &tpType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content_type"), &contentType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("patterns"), &patternsList)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("normalize_addresses"), &normalizeAddresses)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Requirement - Checking content type values in patterns.
-	err := r.validateContentTypeWithPattern(plan.ContentType.ValueStringPointer(), plan.Patterns)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error while Traffic Pattern validation process with Content type field",
-			fmt.Sprintf("Could not create Traffic Pattern, error: %s", err.Error()),
+	// Requirement: Types [filterlist, blacklist] are Read-Only.
+	if !tpType.IsUnknown() && restapi.IsValueInRange(tpType.ValueString(), TrafficPatternROTypes) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Traffic pattern types [blacklist, filterlist] are Read-Only",
+			"Traffic pattern types [blacklist, filterlist] are Read-Only! It is not possible to manage them by Terraform",
 		)
-		return
 	}
 
-	// Requirement - Checking type value in a range of list.
-	if !restapi.IsValueInRange(plan.Type.ValueString(), TrafficPatternTypes) {
-		resp.Diagnostics.AddError(
-			"Traffic pattern type is out of range!",
-			"Traffic pattern type should be in range of [ blacklist, whitelist, filterlist, commonlist ]!",
-		)
+	// Content type dependent requirements could not be checked until content type is known.
+	if contentType.IsUnknown() || contentType.IsNull() {
 		return
 	}
 
-	// Requirement: Types [filterlist, blacklist] are Read-Only.
-	if restapi.IsValueInRange(plan.Type.ValueString(), TrafficPatternROTypes) {
-		resp.Diagnostics.AddError(
-			"Traffic pattern types [blacklist, filterlist] are Read-Only",
-			"Traffic pattern types [blacklist, filterlist] are Read-Only! It is not possible to manage them by Terraform",
+	// Requirement: Only addr patterns could be normalized.
+	if normalizeAddresses.ValueBool() && contentType.ValueString() != "addr" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("normalize_addresses"),
+			"Addresses normalization is not supported for this content type",
+			"Failed - normalize_addresses is supported only for the addr content type",
 		)
-		return
 	}
 
-	// Requirement - Checking content type value in a range of list.
-	if !restapi.IsValueInRange(plan.ContentType.ValueString(), TrafficPatternContentTypes) {
-		resp.Diagnostics.AddError(
-			"Traffic pattern Content Type is out of range!",
-			"Traffic pattern Content Type should be in range of [ addr, commonString, countryCode, httpMethod, asn, md5HashString ]!",
+	// Requirement - Type whitelist is not compatible with content_type httpMethod / countryCode / ...
+	if tpType.ValueString() == "whitelist" && restapi.IsValueInRange(contentType.ValueString(), WhitelistIncompatibleTypes) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content_type"),
+			"Type whitelist is not compatible with content types - [httpMethod, countryCode]",
+			"Failed - Type whitelist is not compatible with next content types - commonString, countryCode, httpMethod, asn, md5HashString",
 		)
+	}
+
+	if patternsList.IsUnknown() || patternsList.IsNull() {
+		return
+	}
+	patterns := []*PatternsModel{}
+	resp.Diagnostics.Append(patternsList.ElementsAs(ctx, &patterns, false)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Requirement - Type whitelist is not compatible with content_type httpMethod / countryCode / ...
-	if plan.Type.ValueString() == "whitelist" {
-		if restapi.IsValueInRange(plan.ContentType.ValueString(), WhitelistIncompatibleTypes) {
-			resp.Diagnostics.AddError(
-				"Type whitelist is not compatible with content types - [httpMethod, countryCode]",
-				"Failed - Type whitelist is not compatible with next content types - commonString, countryCode, httpMethod, asn, md5HashString",
+	r.validatePatterns(contentType.ValueString(), patterns, normalizeAddresses, func(i int, attribute, summary, detail string) {
+		patternPath := path.Root("patterns").AtListIndex(i)
+		if attribute != "" {
+			patternPath = patternPath.AtName(attribute)
+		}
+		resp.Diagnostics.AddAttributeError(patternPath, summary, detail)
+	})
+}
+
+// patternErrorReporter reports a validation error of the pattern with the index,
+// attribute is empty if the error is about the whole pattern.
+type patternErrorReporter func(i int, attribute, summary, detail string)
+
+// validatePatterns checks the patterns against the content type requirements.
+func (r *trafficPatternResource) validatePatterns(contentType string, patterns []*PatternsModel, normalizeAddresses types.Bool, report patternErrorReporter) {
+	for i, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+
+		// Requirement - Checking content type values in patterns.
+		contentErr := r.validateContentTypeWithPattern(contentType, pattern)
+		if contentErr != nil {
+			report(
+				i, PatternContentTypeAttributes[contentType],
+				"Error while Traffic Pattern validation process with Content type field",
+				fmt.Sprintf("Traffic Pattern is not valid, error: %s", contentErr.Error()),
 			)
-			return
 		}
-	}
 
-	// Requirement: TTL and Expires are not compatible with next Content Types.
-	for _, pattern := range plan.Patterns {
-		if restapi.IsValueInRange(plan.ContentType.ValueString(), TtlExpiresIncompatibleTypes) {
+		// Requirement: expires_at is converted to TTL or Expires.
+		if !pattern.ExpiresAt.IsNull() && !pattern.ExpiresAt.IsUnknown() {
 			if !pattern.Ttl.IsNull() || !pattern.Expires.IsNull() {
-				resp.Diagnostics.AddError(
+				report(
+					i, "expires_at",
+					"Fields expires_at and TTL & Expires are not supported together",
+					"Failed - expires_at is sent as TTL or Expires - Assign only one field expires_at, TTL or Expires",
+				)
+			} else if _, _, err := parsePatternExpiresAt(pattern.ExpiresAt.ValueString()); err != nil {
+				report(
+					i, "expires_at",
+					"Invalid expires_at value",
+					fmt.Sprintf("Failed - %s", err.Error()),
+				)
+			}
+		}
+
+		// Requirement: TTL and Expires are not compatible with next Content Types.
+		if restapi.IsValueInRange(contentType, TtlExpiresIncompatibleTypes) {
+			if !pattern.Ttl.IsNull() || !pattern.Expires.IsNull() || !pattern.ExpiresAt.IsNull() {
+				report(
+					i, "",
 					"TTL & Expires are not compatible with this content type",
 					"Failed - TTL & Expires are not compatible with next content Types - commonString, countryCode, httpMethod, asn, md5HashString",
 				)
-				return
 			}
+			continue
+		}
+
+		// Requirement: Both TTL and Expires are not supported.
+		if !pattern.Ttl.IsNull() && !pattern.Expires.IsNull() {
+			report(
+				i, "",
+				"Both fields TTL & Expires are not supported",
+				"Failed - Both fields TTL & Expires are not supported - Assign only one field TTL or Expires",
+			)
+		}
+
+		// Requirement: addr must be a network address with a netmask, unless it is normalized on apply.
+		if contentType != "addr" || contentErr != nil || pattern.Addr.IsUnknown() || normalizeAddresses.IsUnknown() || normalizeAddresses.ValueBool() {
+			continue
+		}
+		prefix, _ := netip.ParsePrefix(pattern.Addr.ValueString())
+		network, _ := patternNetworkAddress(pattern.Addr.ValueString())
+		// Compare IP address with network address after validation.
+		if prefix != network {
+			report(
+				i, "addr",
+				"Invalid IP address",
+				fmt.Sprintf("Failed - IP address %s is NOT equal the network IP address %s, please enter the network IP address instead", pattern.Addr.ValueString(), network),
+			)
+		} else if pattern.Addr.ValueString() != network.String() {
+			// Ngenix stores addresses in the canonical form, e.g. 2001:db8::/32 for 2001:DB8:0::/32.
+			report(
+				i, "addr",
+				"IP address is not in the canonical form",
+				fmt.Sprintf("Failed - IP address %s should be written as %s", pattern.Addr.ValueString(), network),
+			)
 		}
 	}
+}
 
-	// Requirements.
-	// - Both fields TTL & Expires are not supported.
-	// - Expires must not be null.
-	networkWithMask := ""
-	if plan.ContentType.ValueString() == "addr" {
-		for _, pattern := range plan.Patterns {
-			// Validating IP address.
-			networkWithMask, err = restapi.GetNetworkAddressWithMask(pattern.Addr.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"IP address is not suitable, ip address should be in format: ip address / netmask",
-					fmt.Sprintf("Failed - Could no get network address from pattern.addr %s field, error - %s, please add netmask!", pattern.Addr.ValueString(), err),
-				)
+// patternNetworkAddress parses the traffic pattern addr, an IPv4 or IPv6 CIDR
+// block, and returns the network address of the block.
+func patternNetworkAddress(addr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(addr)
+	if err != nil {
+		if ip, ipErr := netip.ParseAddr(addr); ipErr == nil {
+			return netip.Prefix{}, fmt.Errorf("addr value %s has no netmask, use %s/%d for a single address", addr, addr, ip.BitLen())
+		}
+		return netip.Prefix{}, fmt.Errorf("addr value %s is not a valid IPv4 or IPv6 CIDR block", addr)
+	}
+	if prefix.Addr().Is4In6() {
+		return netip.Prefix{}, fmt.Errorf("addr value %s is an IPv4-mapped IPv6 address, use the IPv4 CIDR block instead", addr)
+	}
+	return prefix.Masked(), nil
+}
+
+// ModifyPlan loads the patterns source and shows its hash and the normalized
+// addr patterns in the plan.
+func (r *trafficPatternResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to normalize on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var normalizeAddresses types.Bool
+	var patternsList types.List
+	var contentType, sourceFile, sourceContent, sourceFormat types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("normalize_addresses"), &normalizeAddresses)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("patterns"), &patternsList)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("content_type"), &contentType)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_file"), &sourceFile)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_content"), &sourceContent)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_format"), &sourceFormat)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !normalizeAddresses.IsUnknown() && !normalizeAddresses.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("normalized_patterns"), []*PatternsModel(nil))...)
+	}
+
+	patterns := []*PatternsModel{}
+	if sourceFile.IsNull() && sourceContent.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_hash"), types.StringNull())...)
+		// The normalized patterns are known after apply if any of the values is not known yet.
+		if normalizeAddresses.IsUnknown() || !normalizeAddresses.ValueBool() || patternsList.IsUnknown() {
+			return
+		}
+		resp.Diagnostics.Append(patternsList.ElementsAs(ctx, &patterns, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for _, pattern := range patterns {
+			if pattern != nil && (pattern.Addr.IsUnknown() || pattern.Ttl.IsUnknown() || pattern.Expires.IsUnknown() || pattern.ExpiresAt.IsUnknown() || pattern.Comment.IsUnknown()) {
 				return
 			}
-			// Compare IP address with network address after validation.
-			if pattern.Addr.ValueString() == networkWithMask {
-				fmt.Printf("IP address %s is equal the network address %s", pattern.Addr.ValueString(), networkWithMask)
-			} else {
-				resp.Diagnostics.AddError(
-					"Invalid IP address",
-					fmt.Sprintf("Failed - IP address %s is NOT equal the network IP address %s, please enter the network IP address instead", pattern.Addr.ValueString(), networkWithMask),
-				)
-				return
+		}
+	} else {
+		// The source is loaded at plan time, so changes of the file are shown by its hash.
+		if contentType.IsUnknown() || sourceFile.IsUnknown() || sourceContent.IsUnknown() || sourceFormat.IsUnknown() || normalizeAddresses.IsUnknown() {
+			return
+		}
+		sourcePath := patternSourcePath(sourceFile)
+		sourcePatterns, locations, err := loadPatternSource(sourceFile, sourceContent, sourceFormat, contentType.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				sourcePath,
+				"Error loading Traffic Patterns source",
+				fmt.Sprintf("Could not parse Traffic Patterns source, error: %s", err.Error()),
+			)
+			return
+		}
+		r.validatePatterns(contentType.ValueString(), sourcePatterns, normalizeAddresses, func(i int, _, summary, detail string) {
+			resp.Diagnostics.AddAttributeError(sourcePath, summary, fmt.Sprintf("%s: %s", locations[i], detail))
+		})
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		patterns = sourcePatterns
+	}
+
+	patterns, err := resolvePatternExpirations(patterns)
+	if err != nil {
+		// Invalid expires_at values are reported by ValidateConfig.
+		return
+	}
+	if normalizeAddresses.ValueBool() {
+		normalized, err := normalizeAddrPatterns(patterns)
+		if err != nil {
+			// Invalid addresses are reported by ValidateConfig.
+			return
+		}
+		patterns = normalized
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("normalized_patterns"), patterns)...)
+	}
+	if !sourceFile.IsNull() || !sourceContent.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_hash"), patternsHash(patterns))...)
+	}
+}
+
+// patternsToApply returns the patterns sent to Ngenix, loaded from the source
+// and normalized with normalize_addresses.
+func (m *TrafficPatternResourceModel) patternsToApply() ([]*PatternsModel, error) {
+	patterns := m.Patterns
+	if m.hasPatternSource() {
+		var err error
+		patterns, _, err = loadPatternSource(m.SourceFile, m.SourceContent, m.SourceFormat, m.ContentType.ValueString())
+		if err != nil {
+			return nil, err
+		}
+	}
+	patterns, err := resolvePatternExpirations(patterns)
+	if err != nil {
+		return nil, err
+	}
+	if m.NormalizeAddresses.ValueBool() {
+		if patterns, err = normalizeAddrPatterns(patterns); err != nil {
+			return nil, err
+		}
+	}
+	// The source could be changed between plan and apply.
+	if m.hasPatternSource() && !m.SourceHash.IsUnknown() && m.SourceHash.ValueString() != patternsHash(patterns) {
+		return nil, fmt.Errorf("traffic patterns source was changed after the plan was created, please plan the changes again")
+	}
+	return patterns, nil
+}
+
+// fieldPath maps the fields of the Traffic Pattern rejected by Ngenix API to
+// the resource attributes. patterns[i] is the i-th of the sent patterns, it is
+// shown on the element of patterns with the same value, the patterns loaded
+// from a source have no attributes.
+func (m *TrafficPatternResourceModel) fieldPath(sent []*PatternsModel) func(field apiField) (path.Path, bool) {
+	contentType := m.ContentType.ValueString()
+	return func(field apiField) (path.Path, bool) {
+		switch field.name {
+		case "name", "type", "contentType":
+			return path.Root(attributeName(field.name)), field.index < 0 && field.attribute == ""
+		case "patterns":
+			if m.hasPatternSource() || field.index < 0 || field.index >= len(sent) {
+				return path.Empty(), false
 			}
-			// Both TTL and Expires are not supported.
-			if !pattern.Ttl.IsNull() && !pattern.Expires.IsNull() {
-				resp.Diagnostics.AddError(
-					"Both fields TTL & Expires are not supported",
-					"Failed - Both fields TTL & Expires are not supported - Assign only one field TTL or Expires",
-				)
-				return
+			value := patternValue(sent[field.index], contentType)
+			for i, pattern := range m.Patterns {
+				if pattern == nil || patternValue(pattern, contentType) != value {
+					continue
+				}
+				if field.attribute == "" {
+					return path.Root("patterns").AtListIndex(i), true
+				}
+				return path.Root("patterns").AtListIndex(i).AtName(attributeName(field.attribute)), true
 			}
 		}
+		return path.Empty(), false
+	}
+}
+
+// appliedPatterns returns the patterns sent to Ngenix on the last apply, they
+// are not known for the patterns loaded from a source.
+func (m *TrafficPatternResourceModel) appliedPatterns() ([]*PatternsModel, bool) {
+	switch {
+	case m.NormalizeAddresses.ValueBool():
+		return m.NormalizedPatterns, true
+	case m.hasPatternSource():
+		return nil, false
+	default:
+		if patterns, err := resolvePatternExpirations(m.Patterns); err == nil {
+			return patterns, true
+		}
+		return m.Patterns, true
+	}
+}
+
+// setPatterns sets the patterns read from Ngenix. With normalize_addresses the
+// configured patterns are kept as long as they are normalized to the same list.
+// Patterns loaded from a source are not stored in the state, only their hash.
+// Ngenix does not keep the order of the added patterns, so the known order is
+// kept as long as the patterns are the same. The expired patterns are kept
+// unless recreate_expired is set.
+func (m *TrafficPatternResourceModel) setPatterns(patterns []*PatternsModel) {
+	patterns = m.keepExpiredPatterns(patterns, time.Now())
+	m.setExpirations(patterns, nil, time.Now())
+
+	normalizedPatterns := m.NormalizedPatterns
+	m.NormalizedPatterns = nil
+	m.SourceHash = types.StringNull()
+	if m.NormalizeAddresses.ValueBool() {
+		m.NormalizedPatterns = patterns
+		if samePatterns(normalizedPatterns, patterns) {
+			m.NormalizedPatterns = normalizedPatterns
+		}
 	}
+	if m.hasPatternSource() {
+		m.Patterns = nil
+		m.SourceHash = types.StringValue(patternsHash(patterns))
+		return
+	}
+	// The configured patterns are compared in the form sent to Ngenix.
+	resolved, err := resolvePatternExpirations(m.Patterns)
+	if err != nil {
+		m.Patterns = patterns
+		return
+	}
+	if !m.NormalizeAddresses.ValueBool() {
+		if !samePatterns(resolved, patterns) {
+			m.Patterns = patterns
+		}
+		return
+	}
+	if normalized, err := normalizeAddrPatterns(resolved); err != nil || !samePatterns(normalized, patterns) {
+		m.Patterns = patterns
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *trafficPatternResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan.
+	var plan TrafficPatternResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
 	// Traffic patterns.
-	patterns, err := r.TrafficPatternModelTransformation(plan.Patterns, plan.ContentType.ValueString())
+	patternsToApply, err := plan.patternsToApply()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error while Traffic Patterns creation and validation process",
+			fmt.Sprintf("Could not load Traffic Patterns, error: %s", err.Error()),
+		)
+		return
+	}
+	patterns, err := r.TrafficPatternModelTransformation(patternsToApply, plan.ContentType.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -529,14 +932,21 @@ func (r *trafficPatternResource) Create(ctx context.Context, req resource.Create
 	}
 
 	// Create new Traffic pattern.
-	createdTP, err := r.client.CreateNewTrafficPatternForCustomer(trafficPattern, r.client.CustomerId())
+	createdTP, err := r.client.CreateNewTrafficPatternForCustomer(ctx, trafficPattern, r.client.CustomerId())
 	if err != nil {
+		addAPIErrorDiagnostics(&resp.Diagnostics, "Error creating Traffic pattern", "Could not create Traffic pattern, unexpected error", err,
+			plan.fieldPath(patternsToApply))
+		return
+	}
+	// The ID is taken from the response, a lookup by name could return another list with the same name.
+	if createdTP.ID == nil {
 		resp.Diagnostics.AddError(
 			"Error creating Traffic pattern",
-			fmt.Sprintf("Could not create Traffic pattern, unexpected error: %s", err.Error()),
+			fmt.Sprintf("Traffic pattern %s was created, but the response has no ID", *createdTP.Name),
 		)
 		return
 	}
+	tpId := *createdTP.ID
 
 	// From TrafficPattern to TPModel
 	patternsModel, err := r.TrafficPatternItemModelTransformation(createdTP.Patterns, createdTP.ContentType)
@@ -547,12 +957,12 @@ func (r *trafficPatternResource) Create(ctx context.Context, req resource.Create
 		)
 		return
 	}
-	tpId := r.client.GetTPIDByName(*createdTP.Name)
 	plan.ID = types.StringValue(strconv.Itoa(tpId))
 	plan.Name = types.StringValue(*createdTP.Name)
 	plan.Type = types.StringValue(*createdTP.Type)
 	plan.ContentType = types.StringValue(*createdTP.ContentType)
-	plan.Patterns = patternsModel
+	plan.setExpirations(patternsToApply, patternsToApply, time.Now())
+	plan.setPatterns(patternsModel)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	tflog.Trace(ctx, "Traffic pattern was created successfully!")
@@ -575,10 +985,30 @@ func (r *trafficPatternResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Getting Traffic Pattern by ID
-	tpId, _ := strconv.Atoi(state.ID.ValueString())
-	//tpId := r.client.GetTPIDByName(state.Name.ValueString())
-	trafficPattern, err := r.client.GetTrafficPatternById(tpId)
+	tpId, err := trafficPatternID(state.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Ngenix Traffic Pattern", err.Error())
+		return
+	}
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+	if isNotFound(err) {
+		// Traffic pattern was deleted outside of Terraform, let Terraform re-create it.
+		resp.Diagnostics.AddWarning(
+			"Ngenix Traffic Pattern not found",
+			fmt.Sprintf("Traffic Pattern with ID = %d was not found in Ngenix and is removed from the state, it will be re-created on the next apply.", tpId),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Ngenix Traffic Pattern",
@@ -599,7 +1029,14 @@ func (r *trafficPatternResource) Read(ctx context.Context, req resource.ReadRequ
 	state.Name = types.StringValue(*trafficPattern.Name)
 	state.Type = types.StringValue(*trafficPattern.Type)
 	state.ContentType = types.StringValue(*trafficPattern.ContentType)
-	state.Patterns = patterns
+	// State stored before normalize_addresses was introduced has no value for it.
+	if state.NormalizeAddresses.IsNull() {
+		state.NormalizeAddresses = types.BoolValue(false)
+	}
+	if state.RecreateExpired.IsNull() {
+		state.RecreateExpired = types.BoolValue(false)
+	}
+	state.setPatterns(patterns)
 
 	tflog.Trace(ctx, "Traffic Pattern was read successfully!")
 
@@ -612,89 +1049,69 @@ func (r *trafficPatternResource) Read(ctx context.Context, req resource.ReadRequ
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *trafficPatternResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan.
-	var plan TrafficPatternResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	// Retrieve values from plan and state.
+	var plan, state TrafficPatternResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Requirement - Checking content type values in patterns.
-	err := r.validateContentTypeWithPattern(plan.ContentType.ValueStringPointer(), plan.Patterns)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error while Traffic Pattern validation process with content type field",
-			fmt.Sprintf("Could not create Traffic Pattern, error: %s", err.Error()),
-		)
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
 
-	// Requirement - Checking type value in a range of list.
-	if !restapi.IsValueInRange(plan.Type.ValueString(), TrafficPatternTypes) {
+	// Traffic patterns.
+	patternsToApply, er := plan.patternsToApply()
+	if er != nil {
 		resp.Diagnostics.AddError(
-			"Traffic pattern type is out of range!",
-			"Traffic pattern type should be in range of [ blacklist, whitelist, filterlist, commonlist ]!",
+			"Error while Traffic Patterns creation and validation process",
+			fmt.Sprintf("Could not load Traffic Patterns, error: %s", er.Error()),
 		)
 		return
 	}
 
-	// Requirement: Types [filterlist, blacklist] are Read-Only.
-	if restapi.IsValueInRange(plan.Type.ValueString(), TrafficPatternROTypes) {
-		resp.Diagnostics.AddError(
-			"Traffic pattern types [blacklist, filterlist] are Read-Only",
-			"Traffic pattern types [blacklist, filterlist] are Read-Only! It is not possible to manage them by Terraform",
-		)
+	// Patterns loaded from a source are not stored in the state, the current ones are read from Ngenix.
+	tpId, err := trafficPatternID(plan.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Ngenix Traffic Pattern", err.Error())
 		return
 	}
-
-	// Requirement - Checking content type value in a range of list.
-	if !restapi.IsValueInRange(plan.ContentType.ValueString(), TrafficPatternContentTypes) {
-		resp.Diagnostics.AddError(
-			"Traffic pattern Content Type is out of range!",
-			"Traffic pattern Content Type should be in range of [ addr, commonString, countryCode, httpMethod, asn, md5HashString ]!",
-		)
-		return
+	currentPatterns, ok := state.appliedPatterns()
+	if !ok {
+		currentTrafficPattern, err := r.client.GetTrafficPatternById(ctx, tpId)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Ngenix Traffic Pattern",
+				fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+			)
+			return
+		}
+		if currentPatterns, err = r.TrafficPatternItemModelTransformation(currentTrafficPattern.Patterns, currentTrafficPattern.ContentType); err != nil {
+			resp.Diagnostics.AddError(
+				"Error while Traffic pattern creation and validation process",
+				fmt.Sprintf("Could not create Traffic pattern, error: %s", err.Error()),
+			)
+			return
+		}
 	}
 
-	// Requirements.
-	// - Both fields TTL & Expires are not supported.
-	// - Expires must not be null.
-	networkWithMask := ""
-	if plan.ContentType.ValueString() == "addr" {
-		for _, pattern := range plan.Patterns {
-			// Validating IP address.
-			networkWithMask, err = restapi.GetNetworkAddressWithMask(pattern.Addr.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"IP address is not suitable, ip address should be in format: ip address / netmask",
-					fmt.Sprintf("Failed - Could no get network address from pattern.addr %s field, error - %s, please add netmask!", pattern.Addr.ValueString(), err),
-				)
-				return
-			}
-			// Compare IP address with network address after validation.
-			if pattern.Addr.ValueString() == networkWithMask {
-				fmt.Printf("IP address %s is equal the network address %s", pattern.Addr.ValueString(), networkWithMask)
-			} else {
-				resp.Diagnostics.AddError(
-					"Invalid IP address",
-					fmt.Sprintf("Failed - IP address %s is NOT equal the network IP address %s, please enter the network IP address instead", pattern.Addr.ValueString(), networkWithMask),
-				)
-				return
-			}
-			// Both TTL and Expires are not supported.
-			if !pattern.Ttl.IsNull() && !pattern.Expires.IsNull() {
-				resp.Diagnostics.AddError(
-					"Both fields TTL & Expires are not supported",
-					"Failed - Both fields TTL & Expires are not supported - Assign only one field TTL or Expires",
-				)
-				return
-			}
-		}
+	// Expired patterns kept in the state are not stored by Ngenix anymore,
+	// they are not added again unless recreate_expired is set. The patterns
+	// loaded from a source are compared with the expired ones in the source.
+	now := time.Now()
+	currentPatterns = subtractPatterns(currentPatterns, state.expiredPatterns(currentPatterns, now))
+	plannedPatterns := patternsToApply
+	if !plan.RecreateExpired.ValueBool() {
+		plannedPatterns = subtractPatterns(plannedPatterns, state.expiredPatterns(plannedPatterns, now))
 	}
+	added, _ := diffPatterns(currentPatterns, plannedPatterns)
 
-	// Traffic patterns.
-	patterns, er := r.TrafficPatternModelTransformation(plan.Patterns, plan.ContentType.ValueString())
+	patterns, er := r.TrafficPatternModelTransformation(plannedPatterns, plan.ContentType.ValueString())
 	if er != nil {
 		resp.Diagnostics.AddError(
 			"Error while Traffic Patterns creation and validation process",
@@ -702,20 +1119,17 @@ func (r *trafficPatternResource) Update(ctx context.Context, req resource.Update
 		)
 		return
 	}
-	//
+	// The core library has no partial update of the patterns, the whole list replaces the stored one.
 	trafficPattern := restapi.TrafficPattern{
 		Name:     plan.Name.ValueStringPointer(),
 		Patterns: patterns,
 	}
 
 	// Update existing Traffic pattern.
-	tpId, _ := strconv.Atoi(plan.ID.ValueString())
-	updatedTrafficPattern, errTp := r.client.UpdateTrafficPatternById(trafficPattern, tpId)
+	updatedTrafficPattern, errTp := r.client.UpdateTrafficPatternById(ctx, trafficPattern, tpId)
 	if errTp != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating Ngenix Traffic Pattern",
-			fmt.Sprintf("Could not update Traffic Pattern (PATCH), unexpected error: %s", errTp.Error()),
-		)
+		addAPIErrorDiagnostics(&resp.Diagnostics, "Error Updating Ngenix Traffic Pattern", "Could not update Traffic Pattern (PATCH), unexpected error", errTp,
+			plan.fieldPath(plannedPatterns))
 		return
 	}
 
@@ -728,12 +1142,13 @@ func (r *trafficPatternResource) Update(ctx context.Context, req resource.Update
 		)
 		return
 	}
-	tpId = r.client.GetTPIDByName(*updatedTrafficPattern.Name)
 	plan.ID = types.StringValue(strconv.Itoa(tpId))
 	plan.Name = types.StringValue(*updatedTrafficPattern.Name)
 	plan.Type = types.StringValue(*updatedTrafficPattern.Type)
 	plan.ContentType = types.StringValue(*updatedTrafficPattern.ContentType)
-	plan.Patterns = patternsModel
+	plan.Expirations = state.Expirations
+	plan.setExpirations(patternsToApply, added, now)
+	plan.setPatterns(patternsModel)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	tflog.Trace(ctx, "Traffic pattern was updated successfully!")
@@ -755,9 +1170,26 @@ func (r *trafficPatternResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Delete existing Traffic Pattern.
-	tpId := r.client.GetTPIDByName(state.Name.ValueString())
-	err := r.client.DeleteTrafficPatternById(tpId)
+	tpId, err := trafficPatternID(state.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Ngenix traffic pattern", err.Error())
+		return
+	}
+	err = r.client.DeleteTrafficPatternById(ctx, tpId)
+	if isNotFound(err) {
+		// Traffic pattern is already deleted, nothing to do.
+		tflog.Debug(ctx, "Traffic pattern was already deleted outside of Terraform", map[string]any{"id": tpId})
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting Ngenix traffic pattern",
@@ -774,10 +1206,15 @@ func (r *trafficPatternResource) ImportState(ctx context.Context, req resource.I
 	// Parse the ID from the import ID string. This usually contains the unique identifier for the DNS Zone.
 	resourceID := req.ID
 
+	// The imported state has no timeouts block yet, so the default read timeout is used.
+	ctx, cancel := context.WithTimeout(ctx, defaultReadTimeout)
+	defer cancel()
+
 	// Set the ID field in the state.
 	resp.Diagnostics.Append(
 		resp.State.Set(ctx, &TrafficPatternResourceModel{
-			ID: types.StringValue(resourceID),
+			ID:       types.StringValue(resourceID),
+			Timeouts: nullTimeouts(),
 		})...,
 	)
 	if resp.Diagnostics.HasError() {
@@ -785,10 +1222,14 @@ func (r *trafficPatternResource) ImportState(ctx context.Context, req resource.I
 	}
 
 	// Fetch the Traffic patterns by ID using the client.
-	tpIdInt, _ := strconv.Atoi(resourceID)
-	trafficPattern, err := r.client.GetTrafficPatternById(tpIdInt)
+	tpIdInt, err := trafficPatternID(types.StringValue(resourceID))
+	if err != nil {
+		resp.Diagnostics.AddError("Error importing resource", err.Error())
+		return
+	}
+	trafficPattern, err := r.client.GetTrafficPatternById(ctx, tpIdInt)
 	if err != nil {
-		resp.Diagnostics.AddError("Error fetching resource", fmt.Sprintf("Could not fetch DNS Zone with ID %s: %s", resourceID, err))
+		resp.Diagnostics.AddError("Error fetching resource", fmt.Sprintf("Could not fetch Traffic Pattern with ID %s: %s", resourceID, err))
 		return
 	}
 
@@ -803,12 +1244,17 @@ func (r *trafficPatternResource) ImportState(ctx context.Context, req resource.I
 	}
 	// Writing an updated / imported traffic patterns to the state.
 	state := TrafficPatternResourceModel{
-		ID:          types.StringValue(resourceID),
-		Name:        types.StringValue(*trafficPattern.Name),
-		Type:        types.StringValue(*trafficPattern.Type),
-		ContentType: types.StringValue(*trafficPattern.ContentType),
-		Patterns:    patterns,
-		LastUpdated: types.StringValue(time.Now().Format(time.RFC850)),
+		ID:                 types.StringValue(resourceID),
+		Name:               types.StringValue(*trafficPattern.Name),
+		Type:               types.StringValue(*trafficPattern.Type),
+		ContentType:        types.StringValue(*trafficPattern.ContentType),
+		Patterns:           patterns,
+		NormalizeAddresses: types.BoolValue(false),
+		RecreateExpired:    types.BoolValue(false),
+		Expirations:        types.MapNull(types.StringType),
+		ExpiredPatterns:    types.ListNull(types.StringType),
+		LastUpdated:        types.StringValue(time.Now().Format(time.RFC850)),
+		Timeouts:           nullTimeouts(),
 	}
 
 	tflog.Trace(ctx, "Traffic Pattern was imported successfully!")
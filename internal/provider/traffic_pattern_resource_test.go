@@ -1,9 +1,17 @@
 package provider
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestTrafficPatternAddrResource(t *testing.T) {
@@ -75,6 +83,10 @@ resource "ngenix_traffic_pattern" "test" {
 	{
       addr = "12.12.34.45/32"
       expires = 1924164191
+    },
+    {
+      addr = "2001:db8:1::/48"
+      ttl = 3600
     }
   ]
 }
@@ -89,6 +101,9 @@ resource "ngenix_traffic_pattern" "test" {
 					// Verify third traffic pattern item updated.
 					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.2.addr", "12.12.34.45/32"),
 					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.2.expires", "1924164191"),
+					// Verify IPv6 traffic pattern item added.
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.3.addr", "2001:db8:1::/48"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.3.ttl", "3600"),
 				),
 			},
 			// Delete testing automatically occurs in TestCase.
@@ -268,3 +283,445 @@ resource "ngenix_traffic_pattern" "test" {
 		},
 	})
 }
+
+func TestTrafficPatternResourceValidateConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read-Only types are rejected at plan time.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-ro-tp"
+  type = "blacklist"
+  content_type = "addr"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Traffic pattern types \[blacklist, filterlist\] are Read-Only`),
+			},
+			// Whitelist is not compatible with the httpMethod content type.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-whitelist-tp"
+  type = "whitelist"
+  content_type = "httpMethod"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Type whitelist is not compatible with content types`),
+			},
+			// Every invalid pattern is reported with its path.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-addr-tp"
+  type = "commonlist"
+  content_type = "addr"
+  patterns = [
+    {
+      addr = "98.164.15.0/24"
+    },
+    {
+      addr = "23.56.67.89/24"
+    },
+    {
+      addr = "12.12.34.45/32"
+      ttl = 3600
+      expires = 1924164191
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid IP address.*23.56.67.89/24.*Both fields TTL & Expires are not supported`),
+			},
+			// IPv6 CIDR blocks should be network addresses in the canonical form.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-addr-v6-tp"
+  type = "commonlist"
+  content_type = "addr"
+  patterns = [
+    {
+      addr = "2001:db8::1/32"
+    },
+    {
+      addr = "2001:DB8:1::/48"
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)2001:db8::1/32 is NOT equal the network IP address 2001:db8::/32.*should be written as 2001:db8:1::/48`),
+			},
+			// TTL and expires are not compatible with the asn content type.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-asn-tp"
+  type = "commonlist"
+  content_type = "asn"
+  patterns = [
+    {
+      asn = 3456
+      ttl = 3600
+    }
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`TTL & Expires are not compatible with this content type`),
+			},
+		},
+	})
+}
+
+func TestTrafficPatternResourceDeletedOutOfBand(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	config := providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-out-of-band-tp"
+  type = "commonlist"
+  content_type = "httpMethod"
+  patterns = [
+    {
+      http_method = "GET"
+    }
+  ]
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttrSet("ngenix_traffic_pattern.test", "id"),
+			},
+			// Traffic pattern deleted in the Ngenix UI is planned for re-creation.
+			{
+				PreConfig:          func() { testAccAPI.deleteTrafficPatternByName("tst-out-of-band-tp") },
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Re-creation testing.
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "1"),
+			},
+		},
+	})
+}
+
+func TestTrafficPatternResourceContentTypeChange(t *testing.T) {
+	var createdId string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-content-type-tp"
+  type = "commonlist"
+  content_type = "addr"
+  patterns = [
+    {
+      addr = "98.164.15.0/24"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "content_type", "addr"),
+					func(s *terraform.State) error {
+						createdId = s.RootModule().Resources["ngenix_traffic_pattern.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			// Changing content type destroys the list and creates a new one.
+			{
+				Config: providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-content-type-tp"
+  type = "commonlist"
+  content_type = "asn"
+  patterns = [
+    {
+      asn = 3456
+    }
+  ]
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ngenix_traffic_pattern.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "content_type", "asn"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.0.asn", "3456"),
+					resource.TestCheckResourceAttrWith("ngenix_traffic_pattern.test", "id", func(id string) error {
+						if id == createdId {
+							return fmt.Errorf("expected a new traffic pattern, got the same ID %s", id)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestPatternNetworkAddress(t *testing.T) {
+	testCases := []struct {
+		addr    string
+		network string
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8"},
+		{"10.1.2.3/24", "10.1.2.0/24"},
+		{"2001:db8::/32", "2001:db8::/32"},
+		{"2001:db8::1/32", "2001:db8::/32"},
+		{"2001:DB8:0:0::/64", "2001:db8::/64"},
+		{"10.1.2.3", ""},
+		{"2001:db8::1", ""},
+		{"::ffff:10.1.2.3/128", ""},
+		{"10.1.2.3/33", ""},
+		{"not-an-address", ""},
+	}
+
+	for _, testCase := range testCases {
+		network, err := patternNetworkAddress(testCase.addr)
+		if testCase.network == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error", testCase.addr)
+			}
+			continue
+		}
+		if err != nil || network.String() != testCase.network {
+			t.Errorf("%s: expected %s, got %s, error: %v", testCase.addr, testCase.network, network, err)
+		}
+	}
+}
+
+func TestTrafficPatternResourceNormalizeAddresses(t *testing.T) {
+	config := providerConfig + `
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-normalize-tp"
+  type = "commonlist"
+  content_type = "addr"
+  normalize_addresses = true
+  patterns = [
+    {
+      addr = "10.1.2.3/24"
+    },
+    {
+      addr = "10.1.3.0/24"
+    },
+    {
+      addr = "10.1.2.128/25"
+    },
+    {
+      addr = "2001:db8::1/33"
+    },
+    {
+      addr = "2001:db8:8000::/33"
+    }
+  ]
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, the configured patterns are kept in the state.
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "5"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.0.addr", "10.1.2.3/24"),
+					// Verify the normalized patterns sent to Ngenix.
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "normalized_patterns.#", "2"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "normalized_patterns.0.addr", "10.1.2.0/23"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "normalized_patterns.1.addr", "2001:db8::/32"),
+				),
+			},
+			// The same configuration produces no changes.
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestTrafficPatternResourceSourceFile(t *testing.T) {
+	sourceFile := filepath.Join(t.TempDir(), "networks.csv")
+	writeSource := func(content string) {
+		if err := os.WriteFile(sourceFile, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSource("addr,ttl,comment\n192.0.2.0/24,,office\n2001:db8::/32,3600,\n")
+
+	config := providerConfig + fmt.Sprintf(`
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-source-tp"
+  type = "commonlist"
+  content_type = "addr"
+  source_file = %q
+}
+`, sourceFile)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, only the hash of the source is stored in the state.
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ngenix_traffic_pattern.test", "patterns.#"),
+					resource.TestMatchResourceAttr("ngenix_traffic_pattern.test", "source_hash", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+				),
+			},
+			// The unchanged file produces no changes, even with the lines reordered.
+			{
+				PreConfig: func() { writeSource("addr,comment,ttl\n2001:db8::/32,,3600\n192.0.2.0/24,office,\n") },
+				Config:    config,
+				PlanOnly:  true,
+			},
+			// The changed file is planned for update.
+			{
+				PreConfig: func() { writeSource("addr,ttl,comment\n192.0.2.0/24,,office\n") },
+				Config:    config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ngenix_traffic_pattern.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// Invalid entries are reported with their lines.
+			{
+				PreConfig:   func() { writeSource("addr\n192.0.2.0/24\n192.0.2.1/24\n") },
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`line 3: Failed - IP address 192.0.2.1/24`),
+			},
+		},
+	})
+}
+
+func TestTrafficPatternResourceUpdatePatterns(t *testing.T) {
+	config := func(addrs ...string) string {
+		patterns := []string{}
+		for _, addr := range addrs {
+			patterns = append(patterns, fmt.Sprintf(`
+    {
+      addr = %q
+    }`, addr))
+		}
+		return providerConfig + fmt.Sprintf(`
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-update-patterns-tp"
+  type = "commonlist"
+  content_type = "addr"
+  patterns = [%s
+  ]
+}
+`, strings.Join(patterns, ","))
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: config("192.0.2.0/24", "198.51.100.0/24"),
+				Check:  resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "2"),
+			},
+			// The added pattern is stored.
+			{
+				Config: config("192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"),
+				Check:  resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "3"),
+			},
+			// The removed pattern is not stored anymore, the order of the patterns is kept.
+			{
+				Config: config("203.0.113.0/24", "192.0.2.0/24"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "2"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.0.addr", "203.0.113.0/24"),
+				),
+			},
+			// Nothing changes after refresh.
+			{
+				Config:   config("203.0.113.0/24", "192.0.2.0/24"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestTrafficPatternResourceExpiredPatterns(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	config := func(recreateExpired bool) string {
+		return providerConfig + fmt.Sprintf(`
+resource "ngenix_traffic_pattern" "test" {
+  name = "tst-expiry-tp"
+  type = "commonlist"
+  content_type = "addr"
+  recreate_expired = %t
+  patterns = [
+    {
+      addr = "192.0.2.0/24"
+      expires_at = "72h"
+    },
+    {
+      addr = "198.51.100.0/24"
+      expires_at = %q
+    }
+  ]
+}
+`, recreateExpired, expired)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, expires_at is sent as ttl and expires.
+			{
+				Config: config(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.0.expires_at", "72h"),
+					resource.TestCheckNoResourceAttr("ngenix_traffic_pattern.test", "patterns.0.ttl"),
+					resource.TestMatchResourceAttr("ngenix_traffic_pattern.test", "expirations.192.0.2.0/24", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T`)),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "expirations.198.51.100.0/24", expired),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "expired_patterns.#", "0"),
+				),
+			},
+			// The expired pattern removed by Ngenix is kept and produces no diff.
+			{
+				PreConfig: func() { testAccAPI.expireTrafficPatternPattern("tst-expiry-tp", "198.51.100.0/24") },
+				Config:    config(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "2"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "expired_patterns.#", "1"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "expired_patterns.0", "198.51.100.0/24"),
+				),
+			},
+			{
+				Config:   config(false),
+				PlanOnly: true,
+			},
+			// With recreate_expired the expired pattern is added again.
+			{
+				Config: config(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "patterns.#", "2"),
+					resource.TestCheckResourceAttr("ngenix_traffic_pattern.test", "expired_patterns.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &trafficPatternSingleDataSource{}
+	_ datasource.DataSourceWithConfigure = &trafficPatternSingleDataSource{}
+)
+
+// TrafficPatternSingleDataSource is a helper function to simplify the provider implementation.
+func TrafficPatternSingleDataSource() datasource.DataSource {
+	return &trafficPatternSingleDataSource{}
+}
+
+// trafficPatternSingleDataSource looks up a single Traffic Pattern, including
+// the blacklist and filterlist ones managed by Ngenix.
+type trafficPatternSingleDataSource struct {
+	client *ngenixClient
+}
+
+// trafficPatternSingleDataSourceModel maps the data source schema data.
+type trafficPatternSingleDataSourceModel struct {
+	ID          types.String        `tfsdk:"id"`
+	Name        types.String        `tfsdk:"name"`
+	Type        types.String        `tfsdk:"type"`
+	ContentType types.String        `tfsdk:"content_type"`
+	Patterns    []PatternsDataModel `tfsdk:"patterns"`
+}
+
+// Metadata returns the data source type name.
+func (d *trafficPatternSingleDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_traffic_pattern"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *trafficPatternSingleDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ngenixClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ngenixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Schema defines the schema for the data source.
+func (d *trafficPatternSingleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a single Traffic Pattern by ID or name. Read-only blacklist and filterlist " +
+			"Traffic Patterns managed by Ngenix could be looked up too, so they could be referenced from the rules.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Traffic pattern ID. Exactly one of id and name should be set.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Traffic pattern name. Exactly one of id and name should be set.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Traffic pattern type",
+			},
+			"content_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Traffic pattern content type",
+			},
+			"patterns": schema.ListNestedAttribute{
+				Description: "A list of Traffic patterns.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"addr": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern CIDR block",
+						},
+						"common_string": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern common string",
+						},
+						"country_code": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern country code",
+						},
+						"http_method": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern HTTP method",
+						},
+						"asn": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Traffic pattern ASN",
+						},
+						"md5hash_string": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern MD5 hash string",
+						},
+						"ttl": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Traffic pattern TTL",
+						},
+						"expires": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Traffic pattern expiration date",
+						},
+						"comment": schema.StringAttribute{
+							Computed:    true,
+							Description: "Traffic pattern comment",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *trafficPatternSingleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state trafficPatternSingleDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var trafficPattern *restapi.TrafficPattern
+	if !state.ID.IsNull() {
+		tpId, err := strconv.Atoi(state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id"),
+				"Invalid Traffic Pattern ID",
+				fmt.Sprintf("Traffic Pattern ID should be a number, got: %s", state.ID.ValueString()),
+			)
+			return
+		}
+		trafficPattern, err = d.client.GetTrafficPatternById(ctx, tpId)
+		if isNotFound(err) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id"),
+				"Ngenix Traffic Pattern not found",
+				fmt.Sprintf("Traffic Pattern with ID = %d was not found in Ngenix.", tpId),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Ngenix Traffic Pattern",
+				fmt.Sprintf("Could not read Ngenix Traffic Pattern by ID = %d, error: %s", tpId, err.Error()),
+			)
+			return
+		}
+	} else {
+		trafficPatterns, err := d.client.GetAllTrafficPatternsList(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Ngenix Traffic Pattern",
+				fmt.Sprintf("Could not read Ngenix Traffic Patterns, error: %s", err.Error()),
+			)
+			return
+		}
+		trafficPattern, err = findTrafficPatternByName(trafficPatterns, state.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Ngenix Traffic Pattern not found",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	// Map response body to model.
+	if trafficPattern.ID != nil {
+		state.ID = types.StringValue(strconv.Itoa(*trafficPattern.ID))
+	}
+	state.Name = types.StringPointerValue(trafficPattern.Name)
+	state.Type = types.StringPointerValue(trafficPattern.Type)
+	state.ContentType = types.StringPointerValue(trafficPattern.ContentType)
+	state.Patterns = patternsDataModels(trafficPattern.Patterns)
+
+	tflog.Trace(ctx, "Traffic Pattern was read successfully!")
+
+	// Set state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// findTrafficPatternByName returns the only Traffic Pattern of the list with the name.
+func findTrafficPatternByName(trafficPatterns []restapi.TrafficPattern, name string) (*restapi.TrafficPattern, error) {
+	var found *restapi.TrafficPattern
+	for i, trafficPattern := range trafficPatterns {
+		if trafficPattern.Name == nil || *trafficPattern.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("several traffic patterns are named %s, look it up by id", name)
+		}
+		found = &trafficPatterns[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("traffic pattern with name = %s was not found in Ngenix", name)
+	}
+	return found, nil
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"ngenix/restapi"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestTrafficPatternSingleDataSource(t *testing.T) {
+	testAccFakeAPIOnly(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read-only traffic patterns are looked up by name.
+			{
+				Config: providerConfig + `data "ngenix_traffic_pattern" "blacklist" {
+  name = "seed-blacklist"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.blacklist", "id", "12"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.blacklist", "type", "blacklist"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.blacklist", "content_type", "addr"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.blacklist", "patterns.#", "1"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.blacklist", "patterns.0.addr", "192.0.2.0/24"),
+				),
+			},
+			// Traffic patterns are looked up by ID.
+			{
+				Config: providerConfig + `data "ngenix_traffic_pattern" "commonlist" {
+  id = "11"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.commonlist", "name", "seed-commonlist"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.commonlist", "type", "commonlist"),
+					resource.TestCheckResourceAttr("data.ngenix_traffic_pattern.commonlist", "patterns.0.addr", "10.0.0.0/8"),
+				),
+			},
+			// Traffic patterns of other customers are not found.
+			{
+				Config: providerConfig + `data "ngenix_traffic_pattern" "foreign" {
+  id = "13"
+}`,
+				ExpectError: regexp.MustCompile("Ngenix Traffic Pattern not found"),
+			},
+			{
+				Config: providerConfig + `data "ngenix_traffic_pattern" "missing" {
+  name = "missing"
+}`,
+				ExpectError: regexp.MustCompile("Ngenix Traffic Pattern not found"),
+			},
+			// Exactly one of id and name should be set.
+			{
+				Config:      providerConfig + `data "ngenix_traffic_pattern" "none" {}`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestFindTrafficPatternByName(t *testing.T) {
+	id1, id2, id3 := 1, 2, 3
+	name, other := "allowed", "other"
+	trafficPatterns := []restapi.TrafficPattern{
+		{ID: &id1, Name: &name},
+		{ID: &id2, Name: &other},
+		{ID: &id3, Name: &other},
+		{},
+	}
+
+	found, err := findTrafficPatternByName(trafficPatterns, "allowed")
+	if err != nil || *found.ID != 1 {
+		t.Fatalf("expected traffic pattern 1, got: %v, %v", found, err)
+	}
+	if _, err := findTrafficPatternByName(trafficPatterns, "other"); err == nil {
+		t.Error("expected an error for several traffic patterns with the name")
+	}
+	if _, err := findTrafficPatternByName(trafficPatterns, "missing"); err == nil {
+		t.Error("expected an error for a missing traffic pattern")
+	}
+}
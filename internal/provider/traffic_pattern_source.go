@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// PatternSourceFormats lists the supported formats of source_file and source_content.
+var PatternSourceFormats = []string{"text", "csv", "json"}
+
+// patternSourceCommonAttributes lists the pattern attributes allowed for any content type.
+var patternSourceCommonAttributes = []string{"ttl", "expires", "expires_at", "comment"}
+
+// hasPatternSource reports whether the patterns are loaded from source_file or source_content.
+func (m *TrafficPatternResourceModel) hasPatternSource() bool {
+	return !m.SourceFile.IsNull() || !m.SourceContent.IsNull()
+}
+
+// sourcePatterns returns the patterns loaded from the source in the form sent
+// to Ngenix, false if the source could not be loaded.
+func (m *TrafficPatternResourceModel) sourcePatterns() ([]*PatternsModel, bool) {
+	patterns, _, err := loadPatternSource(m.SourceFile, m.SourceContent, m.SourceFormat, m.ContentType.ValueString())
+	if err != nil {
+		return nil, false
+	}
+	if patterns, err = resolvePatternExpirations(patterns); err != nil {
+		return nil, false
+	}
+	return patterns, true
+}
+
+// patternSourcePath returns the path of the configured patterns source attribute.
+func patternSourcePath(sourceFile types.String) path.Path {
+	if !sourceFile.IsNull() {
+		return path.Root("source_file")
+	}
+	return path.Root("source_content")
+}
+
+// loadPatternSource reads source_file or source_content and parses it to the
+// patterns of the content type. The locations of the patterns in the source,
+// e.g. "line 3", are returned for error messages.
+func loadPatternSource(sourceFile, sourceContent, sourceFormat types.String, contentType string) ([]*PatternsModel, []string, error) {
+	content := sourceContent.ValueString()
+	format := sourceFormat.ValueString()
+	if !sourceFile.IsNull() {
+		data, err := os.ReadFile(sourceFile.ValueString())
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read source file: %w", err)
+		}
+		content = string(data)
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(sourceFile.ValueString())) {
+			case ".csv":
+				format = "csv"
+			case ".json":
+				format = "json"
+			}
+		}
+	}
+	return parsePatternSource(content, format, contentType)
+}
+
+// parsePatternSource parses the patterns source in the format, text is used
+// if the format is empty:
+//   - text: one value per line with an optional "# comment", blank lines and
+//     lines starting with # are skipped;
+//   - csv: a header row with the pattern attribute names, e.g. addr,ttl,comment;
+//   - json: an array of values or of objects with the pattern attribute names.
+func parsePatternSource(content, format, contentType string) ([]*PatternsModel, []string, error) {
+	valueAttribute, ok := PatternContentTypeAttributes[contentType]
+	if !ok {
+		return nil, nil, fmt.Errorf("content_type value is not valid")
+	}
+	switch format {
+	case "", "text":
+		return parsePatternSourceText(content, valueAttribute)
+	case "csv":
+		return parsePatternSourceCsv(content, valueAttribute)
+	case "json":
+		return parsePatternSourceJson(content, valueAttribute)
+	default:
+		return nil, nil, fmt.Errorf("source format %s is not in range [text, csv, json]", format)
+	}
+}
+
+func parsePatternSourceText(content, valueAttribute string) ([]*PatternsModel, []string, error) {
+	patterns, locations := []*PatternsModel{}, []string{}
+	for n, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		location := fmt.Sprintf("line %d", n+1)
+
+		// A comment starts with # after a whitespace, so the values could contain #.
+		value, comment := line, ""
+		for i := 1; i < len(line); i++ {
+			if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+				value, comment = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+				break
+			}
+		}
+		if strings.ContainsAny(value, " \t") {
+			return nil, nil, fmt.Errorf("%s: a single value is expected, got: %q", location, value)
+		}
+
+		pattern := newSourcePattern()
+		if err := setPatternAttribute(pattern, valueAttribute, value); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", location, err)
+		}
+		pattern.Comment = types.StringValue(comment)
+		patterns, locations = append(patterns, pattern), append(locations, location)
+	}
+	return patterns, locations, nil
+}
+
+func parsePatternSourceCsv(content, valueAttribute string) ([]*PatternsModel, []string, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []*PatternsModel{}, []string{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+		if err := checkPatternSourceAttribute(header[i], valueAttribute); err != nil {
+			line, _ := reader.FieldPos(i)
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if !slices.Contains(header, valueAttribute) {
+		return nil, nil, fmt.Errorf("line 1: the header should contain the %s column", valueAttribute)
+	}
+
+	patterns, locations := []*PatternsModel{}, []string{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		location := fmt.Sprintf("line %d", line)
+
+		pattern := newSourcePattern()
+		for i, value := range row {
+			// Empty cells keep the attribute unset.
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			if err := setPatternAttribute(pattern, header[i], value); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", location, err)
+			}
+		}
+		patterns, locations = append(patterns, pattern), append(locations, location)
+	}
+	return patterns, locations, nil
+}
+
+func parsePatternSourceJson(content, valueAttribute string) ([]*PatternsModel, []string, error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal([]byte(content), &entries); err != nil {
+		return nil, nil, fmt.Errorf("an array of patterns is expected: %w", err)
+	}
+
+	patterns, locations := []*PatternsModel{}, []string{}
+	for n, entry := range entries {
+		location := fmt.Sprintf("entry %d", n+1)
+
+		// An entry is either a value or an object with the pattern attributes.
+		attributes := map[string]any{}
+		decoder := json.NewDecoder(bytes.NewReader(entry))
+		decoder.UseNumber()
+		if bytes.HasPrefix(bytes.TrimSpace(entry), []byte("{")) {
+			if err := decoder.Decode(&attributes); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", location, err)
+			}
+		} else {
+			var value any
+			if err := decoder.Decode(&value); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", location, err)
+			}
+			attributes[valueAttribute] = value
+		}
+
+		pattern := newSourcePattern()
+		for attribute, value := range attributes {
+			if err := checkPatternSourceAttribute(attribute, valueAttribute); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", location, err)
+			}
+			switch value := value.(type) {
+			case nil:
+				continue
+			case string, json.Number:
+				if err := setPatternAttribute(pattern, attribute, fmt.Sprint(value)); err != nil {
+					return nil, nil, fmt.Errorf("%s: %w", location, err)
+				}
+			default:
+				return nil, nil, fmt.Errorf("%s: %s should be a string or a number", location, attribute)
+			}
+		}
+		patterns, locations = append(patterns, pattern), append(locations, location)
+	}
+	return patterns, locations, nil
+}
+
+// newSourcePattern returns an empty pattern with the schema defaults.
+func newSourcePattern() *PatternsModel {
+	return &PatternsModel{Comment: types.StringValue("")}
+}
+
+// checkPatternSourceAttribute checks that the source attribute is supported for
+// the content type, values of other content types would never be stored by Ngenix.
+func checkPatternSourceAttribute(attribute, valueAttribute string) error {
+	if attribute != valueAttribute && !slices.Contains(patternSourceCommonAttributes, attribute) {
+		return fmt.Errorf("attribute %q is not supported, expected one of: %s, %s", attribute, valueAttribute, strings.Join(patternSourceCommonAttributes, ", "))
+	}
+	return nil
+}
+
+// setPatternAttribute sets the pattern attribute by its schema name.
+func setPatternAttribute(pattern *PatternsModel, attribute, value string) error {
+	switch attribute {
+	case "addr":
+		pattern.Addr = types.StringValue(value)
+	case "common_string":
+		pattern.CommonString = types.StringValue(value)
+	case "country_code":
+		pattern.CountryCode = types.StringValue(value)
+	case "http_method":
+		pattern.HttpMethod = types.StringValue(value)
+	case "md5hash_string":
+		pattern.Md5HashString = types.StringValue(value)
+	case "expires_at":
+		pattern.ExpiresAt = types.StringValue(value)
+	case "comment":
+		pattern.Comment = types.StringValue(value)
+	case "asn", "ttl", "expires":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s value %q is not an integer", attribute, value)
+		}
+		switch attribute {
+		case "asn":
+			pattern.Asn = types.Int64Value(number)
+		case "ttl":
+			pattern.Ttl = types.Int64Value(number)
+		default:
+			pattern.Expires = types.Int64Value(number)
+		}
+	default:
+		return fmt.Errorf("unknown pattern attribute %q", attribute)
+	}
+	return nil
+}
+
+// patternsHash returns the SHA-256 hash of the patterns in any order, it changes
+// only if the patterns sent to Ngenix change.
+func patternsHash(patterns []*PatternsModel) string {
+	hash := sha256.New()
+	for _, key := range patternKeys(patterns) {
+		fmt.Fprintln(hash, key)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// patternKeys returns the sorted string keys of the patterns.
+func patternKeys(patterns []*PatternsModel) []string {
+	keys := []string{}
+	for _, pattern := range patterns {
+		if pattern != nil {
+			keys = append(keys, patternKey(pattern))
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// patternKey returns the string key of the pattern built from all its attributes.
+func patternKey(pattern *PatternsModel) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s",
+		pattern.Addr, pattern.CommonString, pattern.CountryCode, pattern.HttpMethod, pattern.Asn,
+		pattern.Md5HashString, pattern.Ttl, pattern.Expires, pattern.Comment)
+}
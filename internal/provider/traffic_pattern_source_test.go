@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParsePatternSource(t *testing.T) {
+	pattern := func(addr string, ttl types.Int64, comment string) *PatternsModel {
+		return &PatternsModel{Addr: types.StringValue(addr), Ttl: ttl, Comment: types.StringValue(comment)}
+	}
+	expected := []*PatternsModel{
+		pattern("192.0.2.0/24", types.Int64Null(), "office"),
+		pattern("2001:db8::/32", types.Int64Value(3600), ""),
+	}
+	testCases := map[string]string{
+		"text": "# allowed networks\n\n192.0.2.0/24 # office\r\n2001:db8::/32\n",
+		"csv":  "addr,ttl,comment\n# allowed networks\n192.0.2.0/24,,office\n2001:db8::/32,3600,\n",
+		"json": `[{"addr": "192.0.2.0/24", "comment": "office"}, {"addr": "2001:db8::/32", "ttl": 3600, "expires": null}]`,
+	}
+
+	for format, content := range testCases {
+		patterns, locations, err := parsePatternSource(content, format, "addr")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", format, err)
+			continue
+		}
+		if format == "text" {
+			// The text source has no ttl column.
+			expected[1].Ttl = types.Int64Null()
+		}
+		if !reflect.DeepEqual(patterns, expected) {
+			t.Errorf("%s: unexpected patterns:\n%+v\nexpected:\n%+v", format, patterns, expected)
+		}
+		if len(locations) != len(patterns) {
+			t.Errorf("%s: expected a location per pattern, got: %v", format, locations)
+		}
+		expected[1].Ttl = types.Int64Value(3600)
+	}
+
+	// Values of other content types are set to their attributes.
+	patterns, _, err := parsePatternSource(`[13335, "15169"]`, "json", "asn")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(patterns) != 2 || patterns[0].Asn.ValueInt64() != 13335 || patterns[1].Asn.ValueInt64() != 15169 {
+		t.Errorf("unexpected asn patterns: %+v", patterns)
+	}
+}
+
+func TestParsePatternSourceErrors(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		format  string
+	}{
+		"text with several values": {"192.0.2.0/24 198.51.100.0/24", "text"},
+		"csv without value column": {"ttl,comment\n3600,office", "csv"},
+		"csv with other attribute": {"addr,asn\n192.0.2.0/24,13335", "csv"},
+		"csv with wrong ttl":       {"addr,ttl\n192.0.2.0/24,1h", "csv"},
+		"csv with extra field":     {"addr\n192.0.2.0/24,office", "csv"},
+		"json object":              {`{"addr": "192.0.2.0/24"}`, "json"},
+		"json nested value":        {`[{"addr": ["192.0.2.0/24"]}]`, "json"},
+		"unknown format":           {"192.0.2.0/24", "yaml"},
+	}
+	for name, testCase := range testCases {
+		if _, _, err := parsePatternSource(testCase.content, testCase.format, "addr"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPatternsHash(t *testing.T) {
+	first := &PatternsModel{Addr: types.StringValue("192.0.2.0/24"), Comment: types.StringValue("")}
+	second := &PatternsModel{Addr: types.StringValue("2001:db8::/32"), Comment: types.StringValue("")}
+
+	if patternsHash([]*PatternsModel{first, second}) != patternsHash([]*PatternsModel{second, first}) {
+		t.Errorf("expected the same hash for the patterns in another order")
+	}
+	if patternsHash([]*PatternsModel{first, second}) == patternsHash([]*PatternsModel{first}) {
+		t.Errorf("expected another hash for other patterns")
+	}
+}
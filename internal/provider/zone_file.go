@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ngenix/restapi"
+)
+
+// zoneFileClasses lists the DNS classes of the master file, only IN records are supported.
+var zoneFileClasses = []string{"IN", "CH", "HS", "CS"}
+
+// zoneFileTtlUnits maps the BIND TTL units to seconds.
+var zoneFileTtlUnits = map[byte]int64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+// zoneFileEntry is a single entry of the master file, an entry could span
+// several lines within parentheses.
+type zoneFileEntry struct {
+	line       int
+	blankOwner bool
+	tokens     []string
+}
+
+// parseZoneFile parses the DNS zone master file (RFC 1035) to DNS records with
+// names relative to the zone origin. The zone origin is set by the first $ORIGIN
+// directive if it is empty, while every $ORIGIN directive changes the origin the
+// following relative names are qualified with. SOA records and NS records of the
+// zone apex are skipped, since they are managed by Ngenix.
+func parseZoneFile(content, origin string) ([]restapi.Records, error) {
+	entries, err := splitZoneFile(content)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneOrigin := strings.TrimSuffix(origin, ".")
+	origin = zoneOrigin
+	records := []restapi.Records{}
+	lastOwner := ""
+	for _, entry := range entries {
+		tokens := entry.tokens
+
+		switch strings.ToUpper(tokens[0]) {
+		case "$ORIGIN":
+			if len(tokens) != 2 {
+				return nil, fmt.Errorf("line %d: $ORIGIN directive should have a single domain name", entry.line)
+			}
+			origin = strings.TrimSuffix(qualifyZoneFileName(tokens[1], origin), ".")
+			if zoneOrigin == "" {
+				zoneOrigin = origin
+			}
+			continue
+		case "$TTL":
+			// Ngenix DNS records have no TTL, the directive is only validated.
+			if _, ok := parseZoneFileTtl(tokens[len(tokens)-1]); len(tokens) != 2 || !ok {
+				return nil, fmt.Errorf("line %d: $TTL directive should have a single TTL value", entry.line)
+			}
+			continue
+		case "$INCLUDE", "$GENERATE":
+			return nil, fmt.Errorf("line %d: %s directive is not supported", entry.line, tokens[0])
+		}
+
+		owner := lastOwner
+		if !entry.blankOwner {
+			owner, tokens = tokens[0], tokens[1:]
+		}
+		if owner == "" {
+			return nil, fmt.Errorf("line %d: record owner name is missing", entry.line)
+		}
+		lastOwner = owner
+
+		// TTL and class are optional and could be written in any order,
+		// the TTL is skipped since Ngenix DNS records have no TTL.
+		for i := 0; i < 2 && len(tokens) > 0; i++ {
+			if isDnsTypeInRange(strings.ToUpper(tokens[0]), zoneFileClasses) {
+				if strings.ToUpper(tokens[0]) != "IN" {
+					return nil, fmt.Errorf("line %d: DNS class %s is not supported, only IN records could be imported", entry.line, tokens[0])
+				}
+				tokens = tokens[1:]
+			} else if _, ok := parseZoneFileTtl(tokens[0]); ok {
+				tokens = tokens[1:]
+			}
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: record type is missing", entry.line)
+		}
+
+		recordType, rdata := strings.ToUpper(tokens[0]), tokens[1:]
+		if recordType == "SOA" {
+			continue
+		}
+		name, err := relativeZoneFileName(qualifyZoneFileName(owner, origin), zoneOrigin)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+		if recordType == "NS" && name == "@" {
+			continue
+		}
+		if !isDnsTypeInRange(recordType, DnsRecordTypes) {
+			return nil, fmt.Errorf("line %d: record type %s is not in range [A, CNAME, MX, AAAA, SRV, NS, TXT, CAA]", entry.line, recordType)
+		}
+		data, err := zoneFileRecordData(recordType, rdata, origin)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+		records = append(records, restapi.Records{
+			Name: name,
+			Type: recordType,
+			Data: data,
+		})
+	}
+	return records, nil
+}
+
+// splitZoneFile splits the master file content to entries, removing comments
+// and joining the lines within parentheses.
+func splitZoneFile(content string) ([]zoneFileEntry, error) {
+	entries := []zoneFileEntry{}
+	var entry zoneFileEntry
+	depth := 0
+	for n, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if depth == 0 {
+			entry = zoneFileEntry{
+				line:       n + 1,
+				blankOwner: strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"),
+			}
+		}
+
+		token, quoted := strings.Builder{}, false
+		flush := func() {
+			if token.Len() > 0 {
+				entry.tokens = append(entry.tokens, token.String())
+				token.Reset()
+			}
+		}
+	scan:
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			switch {
+			case quoted:
+				token.WriteByte(c)
+				if c == '\\' && i+1 < len(line) {
+					i++
+					token.WriteByte(line[i])
+				} else if c == '"' {
+					quoted = false
+				}
+			case c == ';':
+				break scan
+			case c == '"':
+				quoted = true
+				token.WriteByte(c)
+			case c == '(':
+				flush()
+				depth++
+			case c == ')':
+				flush()
+				depth--
+				if depth < 0 {
+					return nil, fmt.Errorf("line %d: unbalanced parentheses", n+1)
+				}
+			case c == ' ' || c == '\t':
+				flush()
+			default:
+				token.WriteByte(c)
+			}
+		}
+		if quoted {
+			return nil, fmt.Errorf("line %d: unterminated quoted string", n+1)
+		}
+		flush()
+
+		if depth == 0 && len(entry.tokens) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", entry.line)
+	}
+	return entries, nil
+}
+
+// parseZoneFileTtl parses the TTL in seconds or in BIND units like 1h30m.
+func parseZoneFileTtl(value string) (int64, bool) {
+	if ttl, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return ttl, ttl >= 0
+	}
+	var ttl, number int64
+	digits := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number = number*10 + int64(c-'0')
+			digits = true
+			continue
+		}
+		unit, ok := zoneFileTtlUnits[c|0x20]
+		if !ok || !digits {
+			return 0, false
+		}
+		ttl += number * unit
+		number, digits = 0, false
+	}
+	if digits {
+		return 0, false
+	}
+	return ttl, ttl > 0
+}
+
+// qualifyZoneFileName makes the domain name fully qualified with the origin.
+func qualifyZoneFileName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin + "."
+	case strings.HasSuffix(name, ".") || origin == "":
+		return name
+	default:
+		return name + "." + origin + "."
+	}
+}
+
+// relativeZoneFileName returns the record name relative to the zone origin.
+func relativeZoneFileName(name, origin string) (string, error) {
+	if !strings.HasSuffix(name, ".") {
+		return name, nil
+	}
+	if origin == "" {
+		return "", fmt.Errorf("record name %s is fully qualified, but the zone origin is not set", name)
+	}
+	if sameDnsName(name, origin) {
+		return "@", nil
+	}
+	suffix := "." + origin + "."
+	if len(name) > len(suffix) && strings.EqualFold(name[len(name)-len(suffix):], suffix) {
+		return name[:len(name)-len(suffix)], nil
+	}
+	return "", fmt.Errorf("record name %s is outside of the zone %s", name, origin)
+}
+
+// zoneFileRecordData converts the master file record data to the record data,
+// host names are qualified with the origin.
+func zoneFileRecordData(recordType string, rdata []string, origin string) (string, error) {
+	fieldsCount := map[string]int{"A": 1, "AAAA": 1, "CNAME": 1, "NS": 1, "MX": 2, "SRV": 4, "CAA": 3}
+	if count, ok := fieldsCount[recordType]; ok && len(rdata) != count {
+		return "", fmt.Errorf("%s record should have %d data fields, got: %d", recordType, count, len(rdata))
+	}
+	if len(rdata) == 0 {
+		return "", fmt.Errorf("%s record data is missing", recordType)
+	}
+
+	switch recordType {
+	case "CNAME", "NS":
+		return qualifyZoneFileName(rdata[0], origin), nil
+	case "MX":
+		return rdata[0] + " " + qualifyZoneFileName(rdata[1], origin), nil
+	case "SRV":
+		target := rdata[3]
+		if target != "." {
+			target = qualifyZoneFileName(target, origin)
+		}
+		return strings.Join(append(rdata[:3:3], target), " "), nil
+	case "CAA":
+		return rdata[0] + " " + rdata[1] + " " + strconv.Quote(unquoteZoneFileString(rdata[2])), nil
+	case "TXT":
+		// A single string is stored as is, several strings are kept quoted.
+		if len(rdata) == 1 {
+			return unquoteZoneFileString(rdata[0]), nil
+		}
+		strs := make([]string, 0, len(rdata))
+		for _, str := range rdata {
+			strs = append(strs, quoteZoneFileString(unquoteZoneFileString(str)))
+		}
+		return strings.Join(strs, " "), nil
+	default:
+		return rdata[0], nil
+	}
+}
+
+// unquoteZoneFileString removes the quotes and resolves the escapes of the
+// master file character string, e.g. \" and \DDD.
+func unquoteZoneFileString(value string) string {
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return value
+	}
+	value = value[1 : len(value)-1]
+	var str strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			str.WriteByte(value[i])
+			continue
+		}
+		if i+4 <= len(value) {
+			if code, err := strconv.ParseUint(value[i+1:i+4], 10, 8); err == nil {
+				str.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		i++
+		str.WriteByte(value[i])
+	}
+	return str.String()
+}
+
+// quoteZoneFileString quotes the master file character string.
+func quoteZoneFileString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// formatZoneFile renders the DNS zone records as a master file (RFC 1035).
+// Records pointing to a config or a target group have no master file
+// representation and are written as comments.
+func formatZoneFile(zoneName string, records []restapi.Records) string {
+	var zoneFile strings.Builder
+	fmt.Fprintf(&zoneFile, "$ORIGIN %s.\n", strings.TrimSuffix(zoneName, "."))
+	for _, record := range records {
+		switch {
+		case record.ConfigRef != nil:
+			fmt.Fprintf(&zoneFile, "; %s\tIN\t%s\tconfig_ref %d\n", record.Name, record.Type, record.ConfigRef.ID)
+			continue
+		case record.TargetGroupRef != nil:
+			fmt.Fprintf(&zoneFile, "; %s\tIN\t%s\ttargetgroup_ref %d\n", record.Name, record.Type, record.TargetGroupRef.ID)
+			continue
+		}
+
+		data := record.Data
+		if record.Type == "TXT" && !strings.HasPrefix(data, `"`) {
+			data = quoteZoneFileString(data)
+		}
+		fmt.Fprintf(&zoneFile, "%s\tIN\t%s\t%s\n", record.Name, record.Type, data)
+	}
+	return zoneFile.String()
+}
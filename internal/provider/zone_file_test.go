@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"ngenix/restapi"
+)
+
+const testZoneFile = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.example.com. hostmaster.example.com. (
+		2024010101 ; serial
+		7200       ; refresh
+		3600       ; retry
+		1209600    ; expire
+		3600 )     ; minimum
+@	IN	NS	ns1.ngenix.net.
+@	300	IN	A	192.0.2.10
+	IN	AAAA	2001:db8::10
+www	IN	CNAME	@
+mail	IN	300	CNAME	mx.provider.net.
+@	MX	10 mail ; relative host name
+_sip._tcp.example.com.	IN	SRV	10 5 5060 sip
+@	IN	CAA	0 issue "letsencrypt.org"
+@	IN	TXT	"v=spf1 include:_spf.example.com -all"
+long	IN	TXT	"first part" "second \"part\""
+`
+
+func TestParseZoneFile(t *testing.T) {
+	expected := []restapi.Records{
+		{Name: "@", Type: "A", Data: "192.0.2.10"},
+		{Name: "@", Type: "AAAA", Data: "2001:db8::10"},
+		{Name: "www", Type: "CNAME", Data: "example.com."},
+		{Name: "mail", Type: "CNAME", Data: "mx.provider.net."},
+		{Name: "@", Type: "MX", Data: "10 mail.example.com."},
+		{Name: "_sip._tcp", Type: "SRV", Data: "10 5 5060 sip.example.com."},
+		{Name: "@", Type: "CAA", Data: `0 issue "letsencrypt.org"`},
+		{Name: "@", Type: "TXT", Data: "v=spf1 include:_spf.example.com -all"},
+		{Name: "long", Type: "TXT", Data: `"first part" "second \"part\""`},
+	}
+
+	records, err := parseZoneFile(testZoneFile, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("unexpected records:\n%+v\nexpected:\n%+v", records, expected)
+	}
+
+	// The exported zone file is parsed to the same records.
+	exported, err := parseZoneFile(formatZoneFile("example.com", records), "")
+	if err != nil {
+		t.Fatalf("unexpected error parsing exported zone file: %s", err)
+	}
+	if !reflect.DeepEqual(exported, expected) {
+		t.Fatalf("unexpected exported records:\n%+v\nexpected:\n%+v", exported, expected)
+	}
+}
+
+func TestParseZoneFileNestedOrigin(t *testing.T) {
+	content := `$ORIGIN example.com.
+www	IN	A	192.0.2.10
+$ORIGIN eu
+www	IN	A	192.0.2.20
+@	IN	MX	10 mail
+	IN	TXT	"eu"
+$ORIGIN _tcp.example.com.
+_sip	IN	SRV	10 5 5060 sip.eu.example.com.
+`
+	expected := []restapi.Records{
+		{Name: "www", Type: "A", Data: "192.0.2.10"},
+		{Name: "www.eu", Type: "A", Data: "192.0.2.20"},
+		{Name: "eu", Type: "MX", Data: "10 mail.eu.example.com."},
+		{Name: "eu", Type: "TXT", Data: "eu"},
+		{Name: "_sip._tcp", Type: "SRV", Data: "10 5 5060 sip.eu.example.com."},
+	}
+
+	for _, origin := range []string{"", "example.com."} {
+		records, err := parseZoneFile(content, origin)
+		if err != nil {
+			t.Fatalf("origin %q: unexpected error: %s", origin, err)
+		}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("origin %q: unexpected records:\n%+v\nexpected:\n%+v", origin, records, expected)
+		}
+	}
+
+	// The names of the nested origin outside of the zone are rejected.
+	if _, err := parseZoneFile("$ORIGIN example.org.\nwww IN A 192.0.2.10\n", "example.com"); err == nil {
+		t.Error("expected an error for the record outside of the zone")
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	testCases := map[string]string{
+		"unsupported type":   "@ IN PTR host.example.com.",
+		"unsupported class":  "@ CH A 192.0.2.10",
+		"outside of origin":  "www.example.org. IN A 192.0.2.10",
+		"missing owner":      " IN A 192.0.2.10",
+		"unbalanced":         "@ IN MX ( 10 mail",
+		"unterminated quote": `@ IN TXT "text`,
+		"wrong field count":  "@ IN MX mail",
+		"include":            "$INCLUDE other.zone",
+	}
+	for name, content := range testCases {
+		if _, err := parseZoneFile(content, "example.com"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFormatZoneFile(t *testing.T) {
+	records := []restapi.Records{
+		{Name: "www", Type: "A", Data: "192.0.2.10"},
+		{Name: "cdn", Type: "A", ConfigRef: &restapi.ConfigRef{ID: 88903}},
+		{Name: "@", Type: "TXT", Data: "ngenix-validation-id=dfd32323"},
+	}
+	expected := "$ORIGIN example.com.\n" +
+		"www\tIN\tA\t192.0.2.10\n" +
+		"; cdn\tIN\tA\tconfig_ref 88903\n" +
+		"@\tIN\tTXT\t\"ngenix-validation-id=dfd32323\"\n"
+
+	if zoneFile := formatZoneFile("example.com.", records); zoneFile != expected {
+		t.Errorf("unexpected zone file:\n%s\nexpected:\n%s", zoneFile, expected)
+	}
+}